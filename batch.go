@@ -0,0 +1,155 @@
+package deepl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	maxTextsPerRequest = 50        // deepl accepts at most 50 texts per translate request
+	maxRequestBytes    = 128 << 10 // deepl accepts at most 128 KiB per translate request
+)
+
+// ChunkError Is the failure of a sub-request that translated Count texts starting at Offset
+type ChunkError struct {
+	Offset int
+	Count  int
+	Err    error
+}
+
+func (self *ChunkError) Error() string {
+	return "texts [" + strconv.Itoa(self.Offset) + ", " + strconv.Itoa(self.Offset+self.Count) + "): " + self.Err.Error()
+}
+
+func (self *ChunkError) Unwrap() error {
+	return self.Err
+}
+
+// BatchError Is returned when some sub-requests of a split translation failed.
+// The results of the failed texts are nil, all other results are still returned.
+type BatchError struct {
+	Failures []*ChunkError
+}
+
+func (self *BatchError) Error() string {
+	messages := make([]string, len(self.Failures))
+	for i, failure := range self.Failures {
+		messages[i] = failure.Error()
+	}
+	return fmt.Sprintf("%d of the translate requests failed: %s", len(self.Failures), strings.Join(messages, "; "))
+}
+
+// Unwrap Returns the error of the first failed chunk, so that errors.Is and errors.As work on it
+func (self *BatchError) Unwrap() error {
+	if len(self.Failures) == 0 {
+		return nil
+	}
+	return self.Failures[0].Err
+}
+
+// textBatch Is a sub-range [start, end) of the texts that fits into one request
+type textBatch struct {
+	start, end int
+}
+
+// splitTexts Splits the texts into batches of at most maxCount texts and maxBytes encoded bytes.
+// A single text larger than maxBytes gets its own batch.
+func splitTexts(texts []string, maxCount, maxBytes int) []textBatch {
+	batches := make([]textBatch, 0, len(texts)/maxCount+1)
+	start, size := 0, 0
+	for i, text := range texts {
+		length := encodedLength(text)
+		if i > start && (i-start >= maxCount || size+length > maxBytes) {
+			batches = append(batches, textBatch{start: start, end: i})
+			start, size = i, 0
+		}
+		size += length
+	}
+	if start < len(texts) || len(texts) == 0 {
+		batches = append(batches, textBatch{start: start, end: len(texts)})
+	}
+	return batches
+}
+
+// encodedLength Returns the upper bound of the length of the text as an element of a json array
+func encodedLength(text string) int {
+	length := 3 // quotes and separator
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '"' || c == '\\' || c == '\n' || c == '\r' || c == '\t':
+			length += 2
+		case c < 0x20 || c == '<' || c == '>' || c == '&':
+			length += 6
+		case c >= utf8.RuneSelf:
+			r, width := utf8.DecodeRuneInString(text[i:])
+			if r == utf8.RuneError || r == '\u2028' || r == '\u2029' {
+				length += 6
+			} else {
+				length += width
+			}
+			i += width
+			continue
+		default:
+			length++
+		}
+		i++
+	}
+	return length
+}
+
+// requestOverhead Returns the encoded size of the params without any text
+func (self *Deepl) requestOverhead(body *TextTranslateParams) int {
+	params := *body
+	params.Text = nil
+	encode, err := self.config.JSONEncode(&params)
+	if err != nil {
+		return 1 << 10
+	}
+	return len(encode) + len(`,"text":[]`)
+}
+
+// translateBatches Sends every batch as a separate request with at most config.BatchConcurrency
+// requests in flight, and reassembles the results in the order of the texts
+func (self *Deepl) translateBatches(ctx context.Context, body *TextTranslateParams, batches []textBatch) ([]*TextResult, error) {
+	results := make([]*TextResult, len(body.Text))
+	failures := make([]*ChunkError, len(batches))
+	semaphore := make(chan struct{}, self.config.BatchConcurrency)
+	wg := sync.WaitGroup{}
+	for i, batch := range batches {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(i int, batch textBatch) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			params := *body
+			params.Text = body.Text[batch.start:batch.end]
+			translations, err := self.translateTexts(ctx, &params)
+			if err == nil && len(translations) != len(params.Text) {
+				err = fmt.Errorf("expected %d translations, got %d", len(params.Text), len(translations))
+			}
+			if err != nil {
+				failures[i] = &ChunkError{Offset: batch.start, Count: batch.end - batch.start, Err: err}
+				return
+			}
+			copy(results[batch.start:batch.end], translations)
+		}(i, batch)
+	}
+	wg.Wait()
+	batchErr := &BatchError{}
+	for _, failure := range failures {
+		if failure != nil {
+			batchErr.Failures = append(batchErr.Failures, failure)
+		}
+	}
+	if len(batchErr.Failures) > 0 {
+		return results, batchErr
+	}
+	return results, nil
+}
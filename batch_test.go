@@ -0,0 +1,120 @@
+package deepl
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSplitTexts(t *testing.T) {
+	texts := make([]string, 120)
+	for i := range texts {
+		texts[i] = "text"
+	}
+	batches := splitTexts(texts, 50, 1<<20)
+	want := []textBatch{{0, 50}, {50, 100}, {100, 120}}
+	if len(batches) != len(want) {
+		t.Fatalf("batches = %v, want %v", batches, want)
+	}
+	for i := range want {
+		if batches[i] != want[i] {
+			t.Fatalf("batches = %v, want %v", batches, want)
+		}
+	}
+
+	large := []string{strings.Repeat("a", 60), strings.Repeat("b", 60), strings.Repeat("c", 200), "d"}
+	batches = splitTexts(large, 50, 100)
+	want = []textBatch{{0, 1}, {1, 2}, {2, 3}, {3, 4}}
+	for i := range want {
+		if batches[i] != want[i] {
+			t.Fatalf("batches = %v, want %v", batches, want)
+		}
+	}
+	if batches = splitTexts(nil, 50, 100); len(batches) != 1 {
+		t.Fatalf("empty texts must result in a single batch, got %v", batches)
+	}
+}
+
+func TestEncodedLength(t *testing.T) {
+	for _, text := range []string{"hello", `quote " and \ backslash`, "<b>&</b>", "line\nbreak\ttab\x01", "你好 世界", " "} {
+		encode, _ := json.Marshal(text)
+		if got := encodedLength(text); got < len(encode)+1 {
+			t.Errorf("encodedLength(%q) = %d, encoded length %d", text, got, len(encode))
+		}
+	}
+}
+
+// echoTranslateHandler Translates every text into its upper case, texts starting with "fail" fail the request
+func echoTranslateHandler(t *testing.T, requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		body := TextTranslateParams{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		if len(body.Text) > maxTextsPerRequest {
+			t.Errorf("request contains %d texts", len(body.Text))
+		}
+		result := TextTranslateResultOptional{}
+		for _, text := range body.Text {
+			if strings.HasPrefix(text, "fail") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			result.Translations = append(result.Translations, &TextResult{Text: strings.ToUpper(text), DetectedSourceLanguage: "EN"})
+		}
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func TestDeepl_TextsTranslateBatches(t *testing.T) {
+	var requests int32
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.BatchConcurrency = 3
+	})
+	texts := make([]string, 120)
+	for i := range texts {
+		texts[i] = "text " + strconv.Itoa(i)
+	}
+	results, err := deepl.TextsTranslate(texts, "DE").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 || len(results) != len(texts) {
+		t.Fatalf("got %d results in %d requests", len(results), requests)
+	}
+	for i, result := range results {
+		if result.Text != strings.ToUpper(texts[i]) {
+			t.Fatalf("results[%d] = %s, want %s", i, result.Text, strings.ToUpper(texts[i]))
+		}
+	}
+}
+
+func TestDeepl_TextsTranslateBatchesPartialFailure(t *testing.T) {
+	var requests int32
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Retry = RetryPolicy{MaxAttempts: 1}
+	})
+	texts := make([]string, 120)
+	for i := range texts {
+		texts[i] = "text"
+	}
+	texts[60] = "fail"
+	results, err := deepl.TextsTranslate(texts, "DE").Sync()
+	batchErr := &BatchError{}
+	if !errors.As(err, &batchErr) || len(batchErr.Failures) != 1 {
+		t.Fatalf("err = %v, want a single chunk failure", err)
+	}
+	if failure := batchErr.Failures[0]; failure.Offset != 50 || failure.Count != 50 || !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("unexpected failure %v", failure)
+	}
+	for i, result := range results {
+		if failed := i >= 50 && i < 100; failed != (result == nil) {
+			t.Fatalf("results[%d] = %v", i, result)
+		}
+	}
+}
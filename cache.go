@@ -0,0 +1,262 @@
+package deepl
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheKey Identifies the translation of a single text, all params that change the
+// translation of the text are part of the key. The tag lists are joined by commas in a
+// canonical order, so that keys stay comparable and can be stored in a translation memory.
+type CacheKey struct {
+	Text               string `json:"text"`
+	SourceLang         string `json:"source_lang,omitempty"`
+	TargetLang         string `json:"target_lang"`
+	Formality          string `json:"formality,omitempty"`
+	GlossaryId         string `json:"glossary_id,omitempty"`
+	TagHandling        string `json:"tag_handling,omitempty"`
+	Context            string `json:"context,omitempty"`
+	ModelType          string `json:"model_type,omitempty"`
+	SplitSentences     string `json:"split_sentences,omitempty"`
+	PreserveFormatting bool   `json:"preserve_formatting,omitempty"`
+	OutlineDetection   bool   `json:"outline_detection,omitempty"`
+	NonSplittingTags   string `json:"non_splitting_tags,omitempty"`
+	SplittingTags      string `json:"splitting_tags,omitempty"`
+	IgnoreTags         string `json:"ignore_tags,omitempty"`
+}
+
+func newCacheKey(body *TextTranslateParams, text string) CacheKey {
+	return CacheKey{
+		Text:               text,
+		SourceLang:         body.SourceLang,
+		TargetLang:         body.TargetLang,
+		Formality:          body.Formality,
+		GlossaryId:         body.GlossaryId,
+		TagHandling:        body.TagHandling,
+		Context:            body.Context,
+		ModelType:          body.ModelType,
+		SplitSentences:     body.SplitSentences,
+		PreserveFormatting: body.PreserveFormatting,
+		OutlineDetection:   body.OutlineDetection,
+		NonSplittingTags:   canonicalTags(body.NonSplittingTags),
+		SplittingTags:      canonicalTags(body.SplittingTags),
+		IgnoreTags:         canonicalTags(body.IgnoreTags),
+	}
+}
+
+// canonicalTags Joins the tags sorted and without duplicates, the order of the tags does not change the translation
+func canonicalTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	sorted := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			sorted = append(sorted, tag)
+		}
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// Cache Stores translations of single texts. Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key CacheKey) (*TextResult, bool)
+	Set(key CacheKey, value *TextResult)
+}
+
+// CacheStats Is the hit and miss counters of a cache
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// LRUCache Is an in-memory Cache that evicts the least recently used translation when
+// it is full and treats translations older than the ttl as missing
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[CacheKey]*list.Element
+	order    *list.List
+	hits     uint64
+	misses   uint64
+}
+
+type lruEntry struct {
+	key     CacheKey
+	value   TextResult
+	expires time.Time
+}
+
+// NewLRUCache Creates a cache holding at most capacity translations, a ttl of zero never expires
+func NewLRUCache(capacity int, ttl time.Duration) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[CacheKey]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+func (self *LRUCache) Get(key CacheKey) (*TextResult, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	element, ok := self.items[key]
+	if !ok {
+		self.misses++
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if self.ttl > 0 && time.Now().After(entry.expires) {
+		self.order.Remove(element)
+		delete(self.items, key)
+		self.misses++
+		return nil, false
+	}
+	self.order.MoveToFront(element)
+	self.hits++
+	value := entry.value
+	return &value, true
+}
+
+func (self *LRUCache) Set(key CacheKey, value *TextResult) {
+	if value == nil {
+		return
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	expires := time.Now().Add(self.ttl)
+	if element, ok := self.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = *value
+		entry.expires = expires
+		self.order.MoveToFront(element)
+		return
+	}
+	self.items[key] = self.order.PushFront(&lruEntry{key: key, value: *value, expires: expires})
+	for self.order.Len() > self.capacity {
+		oldest := self.order.Back()
+		self.order.Remove(oldest)
+		delete(self.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Stats Returns the hit and miss counters and the number of cached translations
+func (self *LRUCache) Stats() CacheStats {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return CacheStats{
+		Hits:   self.hits,
+		Misses: self.misses,
+		Size:   self.order.Len(),
+	}
+}
+
+// translateStored Looks up every text in config.Cache and config.Memory and only sends the
+// misses to the api. Stored results are not billed, so their BilledCharacters is zero.
+// Translations that can not be recorded in config.Memory are reported to config.OnMemoryError.
+func (self *Deepl) translateStored(ctx context.Context, body *TextTranslateParams) ([]*TextResult, error) {
+	results := make([]*TextResult, len(body.Text))
+	misses := make([]int, 0, len(body.Text))
+	for i, text := range body.Text {
+		if value, ok := self.lookupStored(newCacheKey(body, text)); ok {
+			value.BilledCharacters = 0
+			results[i] = value
+			continue
+		}
+		misses = append(misses, i)
+	}
+	if len(misses) == 0 {
+		return results, nil
+	}
+	params := *body
+	params.Text = make([]string, len(misses))
+	for i, index := range misses {
+		params.Text[i] = body.Text[index]
+	}
+	translations, err := self.translateUncached(ctx, &params)
+	for i, index := range misses {
+		if i < len(translations) && translations[i] != nil {
+			results[index] = translations[i]
+			// the translation was billed, a failure to persist it must not fail the translation
+			if storeErr := self.store(newCacheKey(body, body.Text[index]), translations[i]); storeErr != nil && self.config.OnMemoryError != nil {
+				self.config.OnMemoryError(storeErr)
+			}
+		}
+	}
+	if batchErr, ok := err.(*BatchError); ok {
+		// map the failed ranges of the sent texts back to the positions of the original texts, a range
+		// is split where cached texts lie between the failed texts so that only failed texts are reported
+		failures := make([]*ChunkError, 0, len(batchErr.Failures))
+		for _, failure := range batchErr.Failures {
+			start := len(failures)
+			for _, index := range misses[failure.Offset : failure.Offset+failure.Count] {
+				if last := len(failures) - 1; last >= start && failures[last].Offset+failures[last].Count == index {
+					failures[last].Count++
+					continue
+				}
+				failures = append(failures, &ChunkError{Offset: index, Count: 1, Err: failure.Err})
+			}
+		}
+		batchErr.Failures = failures
+	}
+	return results, err
+}
+
+// lookupStored Looks up the translation in the cache first and then in the translation memory,
+// translations found in the memory are added to the cache
+func (self *Deepl) lookupStored(key CacheKey) (*TextResult, bool) {
+	if self.config.Cache != nil {
+		if value, ok := self.config.Cache.Get(key); ok {
+			return value, true
+		}
+	}
+	if self.config.Memory == nil {
+		return nil, false
+	}
+	entry, ok := self.config.Memory.Lookup(key)
+	if !ok {
+		return nil, false
+	}
+	value := &TextResult{
+		DetectedSourceLanguage: entry.DetectedSourceLanguage,
+		Text:                   entry.Translation,
+	}
+	if self.config.Cache != nil {
+		self.config.Cache.Set(key, value)
+	}
+	return value, true
+}
+
+// store Adds a translation returned by the api to the cache and the translation memory
+func (self *Deepl) store(key CacheKey, value *TextResult) error {
+	if self.config.Cache != nil {
+		self.config.Cache.Set(key, value)
+	}
+	if self.config.Memory == nil {
+		return nil
+	}
+	err := self.config.Memory.Record(MemoryEntry{
+		Key:                    key,
+		Translation:            value.Text,
+		DetectedSourceLanguage: value.DetectedSourceLanguage,
+		CreatedAt:              time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("record translation memory: %w", err)
+	}
+	return nil
+}
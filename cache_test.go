@@ -0,0 +1,159 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2, 0)
+	a, b, c := CacheKey{Text: "a"}, CacheKey{Text: "b"}, CacheKey{Text: "c"}
+	cache.Set(a, &TextResult{Text: "A"})
+	cache.Set(b, &TextResult{Text: "B"})
+	if value, ok := cache.Get(a); !ok || value.Text != "A" {
+		t.Fatalf("Get(a) = %v, %v", value, ok)
+	}
+	cache.Set(c, &TextResult{Text: "C"})
+	if _, ok := cache.Get(b); ok {
+		t.Fatal("least recently used entry b was not evicted")
+	}
+	if _, ok := cache.Get(a); !ok {
+		t.Fatal("recently used entry a was evicted")
+	}
+	if stats := cache.Stats(); stats != (CacheStats{Hits: 2, Misses: 1, Size: 2}) {
+		t.Fatalf("stats = %+v", stats)
+	}
+	value, _ := cache.Get(a)
+	value.Text = "modified"
+	if value, _ = cache.Get(a); value.Text != "A" {
+		t.Fatal("cached value was modified through the returned result")
+	}
+}
+
+func TestLRUCache_TTL(t *testing.T) {
+	cache := NewLRUCache(10, 10*time.Millisecond)
+	key := CacheKey{Text: "a", TargetLang: "DE"}
+	cache.Set(key, &TextResult{Text: "A"})
+	if _, ok := cache.Get(key); !ok {
+		t.Fatal("entry expired too early")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("entry did not expire")
+	}
+	if stats := cache.Stats(); stats.Size != 0 {
+		t.Fatalf("expired entry was not removed, stats = %+v", stats)
+	}
+}
+
+func TestDeepl_TextsTranslateCache(t *testing.T) {
+	var requests int32
+	cache := NewLRUCache(100, time.Minute)
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Cache = cache
+	})
+	if _, err := deepl.TextsTranslate([]string{"hello", "world"}, "DE").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	results, err := deepl.TextsTranslate([]string{"new", "hello", "world"}, "DE").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Text != "NEW" || results[1].Text != "HELLO" || results[2].Text != "WORLD" {
+		t.Fatalf("unexpected results %v %v %v", results[0], results[1], results[2])
+	}
+	if _, err = deepl.TextsTranslate([]string{"hello", "world"}, "DE").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Fatalf("requests = %d, want 2", requests)
+	}
+	if _, err = deepl.TextsTranslate([]string{"hello"}, "FR").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Fatalf("different target language must not hit the cache, requests = %d", requests)
+	}
+	if stats := cache.Stats(); stats.Hits != 4 || stats.Misses != 4 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestDeepl_TextTranslateCacheTagParams(t *testing.T) {
+	var requests int32
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Cache = NewLRUCache(100, 0)
+	})
+	translate := func(update func(body *TextTranslateParams)) {
+		body := &TextTranslateParams{Text: []string{"<p>hello <x>world</x></p>"}, TagHandling: "xml"}
+		body.TargetLang = "DE"
+		update(body)
+		if _, err := deepl.TextTranslateWithParams(context.Background(), body).Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	translate(func(body *TextTranslateParams) { body.IgnoreTags = []string{"x", "code"} })
+	translate(func(body *TextTranslateParams) { body.IgnoreTags = []string{"code", "x", "x"} })
+	if requests != 1 {
+		t.Fatalf("the same ignore tags in another order missed the cache, requests = %d", requests)
+	}
+	params := []func(body *TextTranslateParams){
+		func(body *TextTranslateParams) { body.IgnoreTags = []string{"code"} },
+		func(body *TextTranslateParams) { body.SplittingTags = []string{"x"} },
+		func(body *TextTranslateParams) { body.NonSplittingTags = []string{"x"} },
+		func(body *TextTranslateParams) { body.SplitSentences = "nonewlines" },
+		func(body *TextTranslateParams) { body.PreserveFormatting = true },
+		func(body *TextTranslateParams) { body.OutlineDetection = true },
+	}
+	for i, update := range params {
+		translate(update)
+		if requests != int32(i+2) {
+			t.Fatalf("params %d were served from the cache of other params, requests = %d", i, requests)
+		}
+	}
+}
+
+func TestDeepl_TextsTranslateCachePartialFailure(t *testing.T) {
+	var requests int32
+	cache := NewLRUCache(1000, 0)
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Cache = cache
+		config.Retry = RetryPolicy{MaxAttempts: 1}
+	})
+	texts := make([]string, 150)
+	for i := range texts {
+		texts[i] = "text " + strconv.Itoa(i)
+	}
+	for i := 0; i < 50; i++ {
+		cache.Set(newCacheKey(&TextTranslateParams{BaseParams: BaseParams{TargetLang: "DE"}}, texts[i*3]), &TextResult{Text: "cached"})
+	}
+	texts[149] = "fail"
+	results, err := deepl.TextsTranslate(texts, "DE").Sync()
+	batchErr := &BatchError{}
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("err = %v, want a chunk failure", err)
+	}
+	// the failed chunk holds the texts 76 to 149 that were not cached, every third text is cached
+	failed := make([]int, 0)
+	for _, failure := range batchErr.Failures {
+		for i := failure.Offset; i < failure.Offset+failure.Count; i++ {
+			failed = append(failed, i)
+			if i%3 == 0 {
+				t.Fatalf("failure %v includes the cached text %d", failure, i)
+			}
+		}
+	}
+	if len(failed) != 50 || failed[0] != 76 || failed[49] != 149 {
+		t.Fatalf("failed texts %v", failed)
+	}
+	if results[0].Text != "cached" || results[1].Text != "TEXT 1" || results[149] != nil {
+		t.Fatalf("unexpected results %v %v %v", results[0], results[1], results[149])
+	}
+	if atomic.LoadInt32(&requests) != 2 {
+		t.Fatalf("requests = %d, want 2", requests)
+	}
+}
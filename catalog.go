@@ -0,0 +1,198 @@
+package deepl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// catalogFailureBackoff Is how long a failed fetch of the catalog is remembered, queries during the
+// backoff return the previous lists if there are any, or the error of the failed fetch
+const catalogFailureBackoff = 30 * time.Second
+
+// LanguageCatalog Caches the languages and the glossary language pairs deepl supports, the lists
+// are fetched on first use and fetched again once they are older than the ttl
+type LanguageCatalog struct {
+	client *Deepl
+	ttl    time.Duration
+
+	mu               sync.Mutex
+	sources          []LanguageResult
+	targets          []LanguageResult
+	sourceIndex      map[Language]LanguageResult
+	targetIndex      map[Language]LanguageResult
+	languagesExpires time.Time
+	languagesErr     error
+	pairs            map[[2]Language]bool
+	pairsExpires     time.Time
+	pairsErr         error
+}
+
+// NewLanguageCatalog Returns a catalog of the languages supported by the account of client,
+// a ttl <= 0 keeps the lists until Invalidate is called
+func NewLanguageCatalog(client *Deepl, ttl time.Duration) *LanguageCatalog {
+	return &LanguageCatalog{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Sources Returns the supported source languages
+func (self *LanguageCatalog) Sources(ctx context.Context) ([]LanguageResult, error) {
+	if err := self.loadLanguages(ctx); err != nil {
+		return nil, err
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return append([]LanguageResult(nil), self.sources...), nil
+}
+
+// Targets Returns the supported target languages
+func (self *LanguageCatalog) Targets(ctx context.Context) ([]LanguageResult, error) {
+	if err := self.loadLanguages(ctx); err != nil {
+		return nil, err
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return append([]LanguageResult(nil), self.targets...), nil
+}
+
+// IsSupportedSource Reports whether deepl accepts the language as source language
+func (self *LanguageCatalog) IsSupportedSource(ctx context.Context, language string) (bool, error) {
+	_, ok, err := self.language(ctx, language, true)
+	return ok, err
+}
+
+// IsSupportedTarget Reports whether deepl accepts the language as target language
+func (self *LanguageCatalog) IsSupportedTarget(ctx context.Context, language string) (bool, error) {
+	_, ok, err := self.language(ctx, language, false)
+	return ok, err
+}
+
+// SupportsFormality Reports whether the formality parameter can be used for the target language
+func (self *LanguageCatalog) SupportsFormality(ctx context.Context, target string) (bool, error) {
+	result, _, err := self.language(ctx, target, false)
+	return result.SupportsFormality, err
+}
+
+// GlossarySupported Reports whether glossaries can be created for the language pair,
+// regional variants are supported when their language is
+func (self *LanguageCatalog) GlossarySupported(ctx context.Context, source, target string) (bool, error) {
+	if err := self.loadPairs(ctx); err != nil {
+		return false, err
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.pairs[[2]Language{NormalizeLanguage(source).Base(), NormalizeLanguage(target).Base()}], nil
+}
+
+// Invalidate Drops the cached lists and failures, so they are fetched again on the next query
+func (self *LanguageCatalog) Invalidate() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.sources, self.targets = nil, nil
+	self.sourceIndex, self.targetIndex = nil, nil
+	self.languagesErr = nil
+	self.pairs = nil
+	self.pairsErr = nil
+}
+
+func (self *LanguageCatalog) language(ctx context.Context, language string, source bool) (LanguageResult, bool, error) {
+	if err := self.loadLanguages(ctx); err != nil {
+		return LanguageResult{}, false, err
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	index := self.targetIndex
+	if source {
+		index = self.sourceIndex
+	}
+	result, ok := index[NormalizeLanguage(language)]
+	return result, ok, nil
+}
+
+// loadLanguages Fetches the source and target languages if they are missing or expired.
+// The lock is not held while fetching, so queries are never blocked by the retries of a fetch.
+func (self *LanguageCatalog) loadLanguages(ctx context.Context) error {
+	self.mu.Lock()
+	fresh := self.sourceIndex != nil && !self.expired(self.languagesExpires)
+	failed := failure(self.languagesErr, self.languagesExpires)
+	self.mu.Unlock()
+	if fresh || failed != nil {
+		return failed
+	}
+	sources, err := self.client.LanguagesWithContext(ctx, LanguagesTypeSource).Sync()
+	var targets []LanguageResult
+	if err == nil {
+		targets, err = self.client.LanguagesWithContext(ctx, LanguagesTypeTarget).Sync()
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if err != nil {
+		return self.fetchFailed(ctx, err, self.sourceIndex != nil, &self.languagesExpires, &self.languagesErr)
+	}
+	self.sources, self.sourceIndex = sources, languageIndex(sources)
+	self.targets, self.targetIndex = targets, languageIndex(targets)
+	self.languagesExpires = time.Now().Add(self.ttl)
+	self.languagesErr = nil
+	return nil
+}
+
+// loadPairs Fetches the glossary language pairs if they are missing or expired, like loadLanguages
+func (self *LanguageCatalog) loadPairs(ctx context.Context) error {
+	self.mu.Lock()
+	fresh := self.pairs != nil && !self.expired(self.pairsExpires)
+	failed := failure(self.pairsErr, self.pairsExpires)
+	self.mu.Unlock()
+	if fresh || failed != nil {
+		return failed
+	}
+	pairs, err := self.client.ListGlossaryPairsWithContext(ctx).Sync()
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if err != nil {
+		return self.fetchFailed(ctx, err, self.pairs != nil, &self.pairsExpires, &self.pairsErr)
+	}
+	self.pairs = make(map[[2]Language]bool, len(pairs))
+	for _, pair := range pairs {
+		self.pairs[[2]Language{NormalizeLanguage(pair.SourceLang).Base(), NormalizeLanguage(pair.TargetLang).Base()}] = true
+	}
+	self.pairsExpires = time.Now().Add(self.ttl)
+	self.pairsErr = nil
+	return nil
+}
+
+// fetchFailed Remembers a failed fetch for catalogFailureBackoff, self.mu must be held. Previous lists
+// keep being used during the backoff, without them the error is returned until the backoff is over.
+// A fetch that failed because ctx is done is not remembered, it says nothing about deepl.
+func (self *LanguageCatalog) fetchFailed(ctx context.Context, err error, stale bool, expires *time.Time, failed *error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	*expires = time.Now().Add(catalogFailureBackoff)
+	if stale {
+		return nil
+	}
+	*failed = err
+	return err
+}
+
+// failure Returns the error of a failed fetch while its backoff lasts
+func failure(err error, expires time.Time) error {
+	if err != nil && time.Now().Before(expires) {
+		return err
+	}
+	return nil
+}
+
+func (self *LanguageCatalog) expired(expires time.Time) bool {
+	return self.ttl > 0 && time.Now().After(expires)
+}
+
+func languageIndex(languages []LanguageResult) map[Language]LanguageResult {
+	index := make(map[Language]LanguageResult, len(languages))
+	for _, language := range languages {
+		index[NormalizeLanguage(language.Language)] = language
+	}
+	return index
+}
@@ -0,0 +1,125 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// catalogHandler Serves the languages and glossary language pairs endpoints, counting the requests
+func catalogHandler(t *testing.T, requests *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		switch r.URL.Path {
+		case languagesUri:
+			if r.URL.Query().Get("type") == LanguagesTypeSource {
+				json.NewEncoder(w).Encode([]LanguageResult{{Language: "DE", Name: "German"}, {Language: "EN", Name: "English"}})
+				return
+			}
+			json.NewEncoder(w).Encode([]LanguageResult{
+				{Language: "DE", Name: "German", SupportsFormality: true},
+				{Language: "EN-GB", Name: "English (British)"},
+				{Language: "EN-US", Name: "English (American)"},
+			})
+		case listGlossaryPairsUri:
+			json.NewEncoder(w).Encode(GlossaryPairsOptional{SupportedLanguages: []PairResult{{SourceLang: "en", TargetLang: "de"}}})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestLanguageCatalog(t *testing.T) {
+	var requests int32
+	catalog := NewLanguageCatalog(newTestDeepl(t, catalogHandler(t, &requests)), time.Minute)
+	ctx := context.Background()
+	tests := []struct {
+		query func() (bool, error)
+		want  bool
+	}{
+		{func() (bool, error) { return catalog.IsSupportedSource(ctx, "en") }, true},
+		{func() (bool, error) { return catalog.IsSupportedSource(ctx, "EN-GB") }, false},
+		{func() (bool, error) { return catalog.IsSupportedTarget(ctx, "en_gb") }, true},
+		{func() (bool, error) { return catalog.IsSupportedTarget(ctx, "FR") }, false},
+		{func() (bool, error) { return catalog.SupportsFormality(ctx, "de") }, true},
+		{func() (bool, error) { return catalog.SupportsFormality(ctx, "EN-US") }, false},
+		{func() (bool, error) { return catalog.GlossarySupported(ctx, "EN", "DE") }, true},
+		{func() (bool, error) { return catalog.GlossarySupported(ctx, "de", "en-us") }, false},
+	}
+	for i, tt := range tests {
+		if ok, err := tt.query(); err != nil || ok != tt.want {
+			t.Errorf("%d: got %v, %v, want %v", i, ok, err, tt.want)
+		}
+	}
+	if targets, err := catalog.Targets(ctx); err != nil || len(targets) != 3 {
+		t.Fatalf("targets %v, %v", targets, err)
+	}
+	if requests != 3 {
+		t.Fatalf("sent %d requests, want 3", requests)
+	}
+	catalog.Invalidate()
+	catalog.IsSupportedTarget(ctx, "DE")
+	if requests != 5 {
+		t.Fatalf("sent %d requests after Invalidate, want 5", requests)
+	}
+}
+
+func TestLanguageCatalog_TTL(t *testing.T) {
+	var requests int32
+	catalog := NewLanguageCatalog(newTestDeepl(t, catalogHandler(t, &requests)), 10*time.Millisecond)
+	catalog.IsSupportedSource(context.Background(), "DE")
+	catalog.IsSupportedSource(context.Background(), "EN")
+	if requests != 2 {
+		t.Fatalf("sent %d requests, want 2", requests)
+	}
+	time.Sleep(20 * time.Millisecond)
+	catalog.IsSupportedSource(context.Background(), "DE")
+	if requests != 4 {
+		t.Fatalf("expired languages were not fetched again, sent %d requests", requests)
+	}
+}
+
+func TestLanguageCatalog_FetchFailure(t *testing.T) {
+	var requests int32
+	var failing atomic.Bool
+	handler := catalogHandler(t, &requests)
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler(w, r)
+	}, func(config *Config) {
+		config.Retry = RetryPolicy{MaxAttempts: 1}
+	})
+	catalog := NewLanguageCatalog(deepl, 10*time.Millisecond)
+	ctx := context.Background()
+	if ok, err := catalog.SupportsFormality(ctx, "DE"); err != nil || !ok {
+		t.Fatalf("got %v, %v", ok, err)
+	}
+	failing.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if ok, err := catalog.SupportsFormality(ctx, "DE"); err != nil || !ok {
+			t.Fatalf("the expired lists were not kept after a failed fetch, got %v, %v", ok, err)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("sent %d requests, want the failed fetch to be remembered", requests)
+	}
+
+	catalog.Invalidate()
+	for i := 0; i < 3; i++ {
+		if _, err := catalog.IsSupportedTarget(ctx, "DE"); err == nil {
+			t.Fatal("a failed fetch without previous lists returned no error")
+		}
+	}
+	if requests != 4 {
+		t.Fatalf("sent %d requests, want 4", requests)
+	}
+}
@@ -0,0 +1,174 @@
+package deepl
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	paragraphRegex  = regexp.MustCompile(`\n[^\S\n]*\n\s*`)
+	sentenceRegex   = regexp.MustCompile(`[.!?…]+["'”’)\]]*\s+|[。！？]+["'”’」』)\]]*\s*`)
+	whitespaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// textChunk Is a part of a long text, Separator is the original text between it and the next chunk
+type textChunk struct {
+	Text      string
+	Separator string
+}
+
+// splitLongText Splits the text into chunks of at most maxBytes bytes. The text is split on
+// paragraph boundaries first, pieces that are still too large are split on sentence
+// boundaries, then on whitespace and finally on rune boundaries. Concatenating every
+// Text and Separator of the result yields the original text.
+func splitLongText(text string, maxBytes int) []textChunk {
+	pieces := splitRecursive(text, maxBytes, []*regexp.Regexp{paragraphRegex, sentenceRegex, whitespaceRegex})
+	chunks := make([]textChunk, 0, len(pieces))
+	for _, piece := range pieces {
+		if n := len(chunks); n > 0 {
+			last := &chunks[n-1]
+			if len(last.Text)+len(last.Separator)+len(piece.Text) <= maxBytes {
+				last.Text += last.Separator + piece.Text
+				last.Separator = piece.Separator
+				continue
+			}
+		}
+		chunks = append(chunks, piece)
+	}
+	return chunks
+}
+
+func splitRecursive(text string, maxBytes int, regexes []*regexp.Regexp) []textChunk {
+	if len(text) <= maxBytes {
+		return []textChunk{{Text: text}}
+	}
+	if len(regexes) == 0 {
+		return splitRunes(text, maxBytes)
+	}
+	result := make([]textChunk, 0)
+	start := 0
+	for _, match := range regexes[0].FindAllStringIndex(text, -1) {
+		// the separator is the trailing whitespace of the match, punctuation stays with the text
+		end := match[1]
+		separatorStart := len(strings.TrimRightFunc(text[:end], unicode.IsSpace))
+		if separatorStart < match[0] {
+			separatorStart = match[0]
+		}
+		if separatorStart == 0 || end == len(text) && separatorStart == end {
+			continue
+		}
+		pieces := splitRecursive(text[start:separatorStart], maxBytes, regexes[1:])
+		pieces[len(pieces)-1].Separator = text[separatorStart:end]
+		result = append(result, pieces...)
+		start = end
+	}
+	return append(result, splitRecursive(text[start:], maxBytes, regexes[1:])...)
+}
+
+// splitRunes Cuts the text into pieces of at most maxBytes bytes without splitting a rune
+func splitRunes(text string, maxBytes int) []textChunk {
+	result := make([]textChunk, 0, len(text)/maxBytes+1)
+	for len(text) > maxBytes {
+		end := maxBytes
+		for end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		if end == 0 {
+			_, end = utf8.DecodeRuneInString(text)
+		}
+		result = append(result, textChunk{Text: text[:end]})
+		text = text[end:]
+	}
+	return append(result, textChunk{Text: text})
+}
+
+// chunkContext Returns the text surrounding the chunk at index, at most maxBytes bytes on each side
+func chunkContext(chunks []textChunk, index, maxBytes int) string {
+	builder := strings.Builder{}
+	if index > 0 {
+		previous := chunks[index-1].Text
+		start := len(previous) - maxBytes
+		if start < 0 {
+			start = 0
+		}
+		for start < len(previous) && !utf8.RuneStart(previous[start]) {
+			start++
+		}
+		builder.WriteString(previous[start:])
+	}
+	if index < len(chunks)-1 {
+		next := chunks[index+1].Text
+		end := maxBytes
+		if end >= len(next) {
+			end = len(next)
+		} else {
+			for end > 0 && !utf8.RuneStart(next[end]) {
+				end--
+			}
+		}
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(next[:end])
+	}
+	return builder.String()
+}
+
+// LongTextTranslate Is translate a single text of any length
+func (self *Deepl) LongTextTranslate(text, target string) *CMD[*TextResult] {
+	body := &TextTranslateParams{}
+	body.TargetLang = target
+	return self.LongTextTranslateWithParams(context.Background(), text, body)
+}
+
+// LongTextTranslateWithParams Texts larger than config.ChunkSize are split on paragraph,
+// sentence and whitespace boundaries and every chunk is translated in a separate request,
+// with the neighbouring text passed as Context. The translated chunks are joined with the
+// original separators. body.Text is ignored, the other params apply to every chunk.
+func (self *Deepl) LongTextTranslateWithParams(ctx context.Context, text string, body *TextTranslateParams) *CMD[*TextResult] {
+	return NewCMD(ctx, func() (*TextResult, error) {
+		return self.doLongTextTranslate(ctx, text, body)
+	})
+}
+
+func (self *Deepl) doLongTextTranslate(ctx context.Context, text string, body *TextTranslateParams) (*TextResult, error) {
+	trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+	prefix := text[:len(text)-len(trimmed)]
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	suffix := text[len(prefix)+len(trimmed):]
+	chunks := splitLongText(trimmed, self.config.ChunkSize)
+	result := &TextResult{}
+	builder := strings.Builder{}
+	builder.WriteString(prefix)
+	for i, chunk := range chunks {
+		if chunk.Text == "" {
+			builder.WriteString(chunk.Separator)
+			continue
+		}
+		params := *body
+		params.Text = []string{chunk.Text}
+		if len(chunks) > 1 && self.config.ChunkContextSize > 0 {
+			params.Context = strings.TrimSpace(body.Context + "\n" + chunkContext(chunks, i, self.config.ChunkContextSize))
+		}
+		translations, err := self.doTextTranslate(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		if len(translations) == 0 {
+			continue
+		}
+		if result.DetectedSourceLanguage == "" {
+			result.DetectedSourceLanguage = translations[0].DetectedSourceLanguage
+			result.ModelTypeUsed = translations[0].ModelTypeUsed
+		}
+		result.BilledCharacters += translations[0].BilledCharacters
+		builder.WriteString(translations[0].Text)
+		builder.WriteString(chunk.Separator)
+	}
+	builder.WriteString(suffix)
+	result.Text = builder.String()
+	return result, nil
+}
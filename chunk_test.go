@@ -0,0 +1,140 @@
+package deepl
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func joinChunks(chunks []textChunk) string {
+	builder := strings.Builder{}
+	for _, chunk := range chunks {
+		builder.WriteString(chunk.Text + chunk.Separator)
+	}
+	return builder.String()
+}
+
+func TestSplitLongText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxBytes int
+		want     []textChunk
+	}{
+		{
+			name:     "fits",
+			text:     "Hello world.",
+			maxBytes: 100,
+			want:     []textChunk{{Text: "Hello world."}},
+		},
+		{
+			name:     "paragraphs",
+			text:     "First paragraph.\n\nSecond paragraph.\n  \nThird paragraph.",
+			maxBytes: 20,
+			want: []textChunk{
+				{Text: "First paragraph.", Separator: "\n\n"},
+				{Text: "Second paragraph.", Separator: "\n  \n"},
+				{Text: "Third paragraph."},
+			},
+		},
+		{
+			name:     "paragraphs packed",
+			text:     "One.\n\nTwo.\n\nThree is longer.",
+			maxBytes: 12,
+			want: []textChunk{
+				{Text: "One.\n\nTwo.", Separator: "\n\n"},
+				{Text: "Three is", Separator: " "},
+				{Text: "longer."},
+			},
+		},
+		{
+			name:     "sentences",
+			text:     "First sentence. Second one! Third?",
+			maxBytes: 16,
+			want: []textChunk{
+				{Text: "First sentence.", Separator: " "},
+				{Text: "Second one!", Separator: " "},
+				{Text: "Third?"},
+			},
+		},
+		{
+			name:     "cjk sentences",
+			text:     "第一句。第二句！第三句？",
+			maxBytes: 13,
+			want: []textChunk{
+				{Text: "第一句。"},
+				{Text: "第二句！"},
+				{Text: "第三句？"},
+			},
+		},
+		{
+			name:     "runes",
+			text:     "你好世界",
+			maxBytes: 7,
+			want: []textChunk{
+				{Text: "你好"},
+				{Text: "世界"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitLongText(tt.text, tt.maxBytes)
+			if joinChunks(got) != tt.text {
+				t.Fatalf("joined chunks %q, want %q", joinChunks(got), tt.text)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("chunks = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("chunks = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestChunkContext(t *testing.T) {
+	chunks := []textChunk{{Text: "first chunk"}, {Text: "middle"}, {Text: "last chunk"}}
+	if got := chunkContext(chunks, 1, 5); got != "chunk\nlast " {
+		t.Fatalf("context = %q", got)
+	}
+	if got := chunkContext(chunks, 0, 100); got != "middle" {
+		t.Fatalf("context = %q", got)
+	}
+	if got := chunkContext([]textChunk{{Text: "你好"}, {Text: "x"}}, 1, 4); got != "好" {
+		t.Fatalf("context = %q", got)
+	}
+}
+
+func TestDeepl_LongTextTranslate(t *testing.T) {
+	var contexts []string
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		body := TextTranslateParams{}
+		json.NewDecoder(r.Body).Decode(&body)
+		contexts = append(contexts, body.Context)
+		json.NewEncoder(w).Encode(TextTranslateResultOptional{Translations: []*TextResult{
+			{Text: strings.ToUpper(body.Text[0]), DetectedSourceLanguage: "EN", BilledCharacters: len(body.Text[0])},
+		}})
+	}, func(config *Config) {
+		config.ChunkSize = 20
+		config.ChunkContextSize = 6
+	})
+	text := "\n  First paragraph.\n\nSecond sentence. Third sentence.  \n"
+	result, err := deepl.LongTextTranslate(text, "DE").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.ToUpper(text); result.Text != want {
+		t.Fatalf("text = %q, want %q", result.Text, want)
+	}
+	if result.DetectedSourceLanguage != "EN" || result.BilledCharacters != len("First paragraph.Second sentence.Third sentence.") {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	want := []string{"Second", "graph.\nThird", "tence."}
+	if strings.Join(contexts, "|") != strings.Join(want, "|") {
+		t.Fatalf("contexts = %q, want %q", contexts, want)
+	}
+}
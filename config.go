@@ -2,6 +2,7 @@ package deepl
 
 import (
 	"encoding/json"
+	"net/http"
 	"time"
 )
 
@@ -9,16 +10,47 @@ type JSONMarshal func(v any) ([]byte, error)
 type JSONUnmarshaler func(data []byte, v any) error
 
 type Config struct {
-	AuthKey     string        // deepl api authKey
-	Timeout     time.Duration // request timeout
-	AccountType int           // deepl account type free|pro
-	JSONEncode  JSONMarshal
-	JSONDecode  JSONUnmarshaler
+	AuthKey                   string        // deepl api authKey
+	Timeout                   time.Duration // request timeout
+	AccountType               int           // deepl account type free|pro, inferred from AuthKey when unset
+	JSONEncode                JSONMarshal
+	JSONDecode                JSONUnmarshaler
+	BaseURL                   string            // api host override, e.g. a mock server, must not contain the api version
+	HTTPClient                *http.Client      // shared http client, Timeout and Transport are ignored when set
+	Transport                 http.RoundTripper // transport of the default http client, e.g. an egress proxy
+	Retry                     RetryPolicy       // retry policy of failed requests, DefaultConfig.Retry is used when unset
+	RequestsPerSecond         float64           // client-side limit of requests per second, zero means unlimited
+	CharactersPerMinute       int               // client-side limit of translated or improved characters per minute, zero means unlimited
+	Middlewares               []Middleware      // wrap every request in order, e.g. logging, metrics or fault injection
+	BatchConcurrency          int               // maximum concurrent requests when a text translation is split into several requests
+	ChunkSize                 int               // byte budget of a single chunk of long text translations
+	ChunkContextSize          int               // bytes of the neighbouring chunks passed as context of long text translations, negative disables it
+	Cache                     Cache             // translations of single texts are looked up before they are sent, e.g. NewLRUCache
+	Memory                    TranslationMemory // persistent translations looked up after Cache, e.g. OpenFileMemory
+	DocumentPollInterval      time.Duration     // minimum interval between two status checks of a document translation
+	DocumentPollMaxInterval   time.Duration     // maximum interval between two status checks of a document translation
+	DocumentNamer             DocumentNamer     // file name of documents translated from files, DefaultDocumentNamer is used when unset
+	DisableLanguageValidation bool              // send source and target languages unchanged, e.g. for languages newer than this client
+	LanguageCatalogTTL        time.Duration     // how long the languages of the client's LanguageCatalog are cached
+	StrictFormality           bool              // reject a hard formality for target languages without formality support instead of downgrading it
+	OnMemoryError             func(err error)   // called when a translation could not be recorded in Memory, the translation is returned anyway
 }
 
 var DefaultConfig = Config{
-	Timeout:     10 * time.Second,
-	AccountType: FreeAccount,
-	JSONEncode:  json.Marshal,
-	JSONDecode:  json.Unmarshal,
+	Timeout:    10 * time.Second,
+	JSONEncode: json.Marshal,
+	JSONDecode: json.Unmarshal,
+	Retry: RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+	},
+	BatchConcurrency:        1,
+	ChunkSize:               16 << 10,
+	ChunkContextSize:        512,
+	DocumentPollInterval:    time.Second,
+	DocumentPollMaxInterval: 30 * time.Second,
+	DocumentNamer:           DefaultDocumentNamer,
+	LanguageCatalogTTL:      24 * time.Hour,
 }
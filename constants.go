@@ -6,24 +6,29 @@ const (
 )
 
 const (
-	freeHost = "https://api-free.deepl.com/v2"
-	proHost  = "https://api.deepl.com/v2"
+	freeHost = "https://api-free.deepl.com"
+	proHost  = "https://api.deepl.com"
 )
 
 const (
-	textTranslateUri       = "/translate"
-	documentTranslateUri   = "/document"
-	checkDocumentStatusUri = "/document/%s"
-	downloadDocumentsUri   = "/document/%s/result"
-	usageUri               = "/usage"
-	languagesUri           = "/languages"
-	textImprovementUri     = "/write/rephrase"
-	listGlossaryPairsUri   = "/glossary-language-pairs"
-	createGlossaryUri      = "/glossaries"
-	listGlossariesUri      = "/glossaries"
-	glossaryDetailsUri     = "/glossaries/%s"
-	glossaryEntriesUri     = "/glossaries/%s/entries"
-	deleteGlossaryUri      = "/glossaries/%s"
+	textTranslateUri       = "/v2/translate"
+	documentTranslateUri   = "/v2/document"
+	checkDocumentStatusUri = "/v2/document/%s"
+	downloadDocumentsUri   = "/v2/document/%s/result"
+	usageUri               = "/v2/usage"
+	languagesUri           = "/v2/languages"
+	textImprovementUri     = "/v2/write/rephrase"
+	listGlossaryPairsUri   = "/v2/glossary-language-pairs"
+	createGlossaryUri      = "/v2/glossaries"
+	listGlossariesUri      = "/v2/glossaries"
+	glossaryDetailsUri     = "/v2/glossaries/%s"
+	glossaryEntriesUri     = "/v2/glossaries/%s/entries"
+	deleteGlossaryUri      = "/v2/glossaries/%s"
+
+	multilingualGlossariesUri    = "/v3/glossaries"
+	multilingualGlossaryUri      = "/v3/glossaries/%s"
+	multilingualDictionariesUri  = "/v3/glossaries/%s/dictionaries"
+	multilingualGlossaryEntryUri = "/v3/glossaries/%s/entries"
 )
 
 var (
@@ -83,6 +88,17 @@ const (
 	DocumentStatusDone        = "done"
 	DocumentStatusError       = "error"
 
+	ManifestStatusPending  = "pending"
+	ManifestStatusUploaded = "uploaded"
+	ManifestStatusDone     = "done"
+	ManifestStatusFailed   = "failed"
+
+	ModelTypeQualityOptimized       = "quality_optimized"
+	ModelTypePreferQualityOptimized = "prefer_quality_optimized"
+	ModelTypeLatencyOptimized       = "latency_optimized"
+
 	EntriesFormatTSV = "tsv"
 	EntriesFormatCSV = "csv"
+
+	EntriesAcceptTSV = "text/tab-separated-values"
 )
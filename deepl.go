@@ -4,12 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"go/types"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 )
 
 var (
@@ -19,20 +19,35 @@ var (
 )
 
 type Deepl struct {
-	client *http.Client
-	config Config
-	host   string
-}
-
+	client           *http.Client
+	handler          RequestHandler
+	config           Config
+	host             string
+	requestLimiter   *tokenBucket
+	characterLimiter *tokenBucket
+	catalog          *LanguageCatalog
+}
+
+// NewDeepl Is create deepl client. Keys ending in ":fx" belong to a free account,
+// all other keys belong to a pro account. When config.AccountType is unset the
+// account type is inferred from the key, otherwise it must match the key.
 func NewDeepl(config Config) (*Deepl, error) {
-	if !strings.HasSuffix(config.AuthKey, ":fx") || !uuidRegex.MatchString(config.AuthKey[:len(config.AuthKey)-3]) {
+	keyType, ok := accountTypeOf(config.AuthKey)
+	if !ok {
 		return nil, fmt.Errorf("Token does not exist or is not formatted correctly, your Token: %s ", config.AuthKey)
 	}
 	if config.Timeout == 0 {
 		config.Timeout = DefaultConfig.Timeout
 	}
-	if config.AccountType < 1 || config.AccountType > 2 {
-		config.AccountType = DefaultConfig.AccountType
+	switch config.AccountType {
+	case 0:
+		config.AccountType = keyType
+	case FreeAccount, ProAccount:
+		if config.AccountType != keyType {
+			return nil, fmt.Errorf("AccountType %s does not match the Token, the Token belongs to a %s account", accountTypeName(config.AccountType), accountTypeName(keyType))
+		}
+	default:
+		return nil, fmt.Errorf("AccountType is not supported, your AccountType: %d ", config.AccountType)
 	}
 	if config.JSONEncode == nil {
 		config.JSONEncode = DefaultConfig.JSONEncode
@@ -40,18 +55,80 @@ func NewDeepl(config Config) (*Deepl, error) {
 	if config.JSONDecode == nil {
 		config.JSONDecode = DefaultConfig.JSONDecode
 	}
-	client := &http.Client{
-		Timeout: config.Timeout,
+	if config.Retry == (RetryPolicy{}) {
+		config.Retry = DefaultConfig.Retry
+	}
+	if config.Retry.MaxAttempts < 1 {
+		config.Retry.MaxAttempts = 1
+	}
+	if config.BatchConcurrency < 1 {
+		config.BatchConcurrency = DefaultConfig.BatchConcurrency
+	}
+	if config.ChunkSize < 1 {
+		config.ChunkSize = DefaultConfig.ChunkSize
+	}
+	if config.ChunkContextSize == 0 {
+		config.ChunkContextSize = DefaultConfig.ChunkContextSize
+	}
+	if config.DocumentNamer == nil {
+		config.DocumentNamer = DefaultDocumentNamer
+	}
+	if config.LanguageCatalogTTL == 0 {
+		config.LanguageCatalogTTL = DefaultConfig.LanguageCatalogTTL
+	}
+	if config.DocumentPollInterval <= 0 {
+		config.DocumentPollInterval = DefaultConfig.DocumentPollInterval
+	}
+	if config.DocumentPollMaxInterval < config.DocumentPollInterval {
+		config.DocumentPollMaxInterval = DefaultConfig.DocumentPollMaxInterval
+		if config.DocumentPollMaxInterval < config.DocumentPollInterval {
+			config.DocumentPollMaxInterval = config.DocumentPollInterval
+		}
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
+		}
 	}
 	host := freeHost
 	if config.AccountType == ProAccount {
 		host = proHost
 	}
-	return &Deepl{
-		client: client,
-		config: config,
-		host:   host,
-	}, nil
+	if config.BaseURL != "" {
+		host = strings.TrimRight(config.BaseURL, "/")
+	}
+	deepl := &Deepl{
+		client:           client,
+		handler:          chainMiddlewares(client.Do, config.Middlewares),
+		config:           config,
+		host:             host,
+		requestLimiter:   newTokenBucket(config.RequestsPerSecond, config.RequestsPerSecond),
+		characterLimiter: newTokenBucket(float64(config.CharactersPerMinute)/60, float64(config.CharactersPerMinute)),
+	}
+	deepl.catalog = NewLanguageCatalog(deepl, config.LanguageCatalogTTL)
+	return deepl, nil
+}
+
+// Catalog Returns the language catalog the client uses to look up formality support
+func (self *Deepl) Catalog() *LanguageCatalog {
+	return self.catalog
+}
+
+// accountTypeOf Returns the account type the authKey belongs to and whether the key is well-formed
+func accountTypeOf(authKey string) (int, bool) {
+	if strings.HasSuffix(authKey, ":fx") {
+		return FreeAccount, uuidRegex.MatchString(authKey[:len(authKey)-3])
+	}
+	return ProAccount, uuidRegex.MatchString(authKey)
+}
+
+func accountTypeName(accountType int) string {
+	if accountType == ProAccount {
+		return "pro"
+	}
+	return "free"
 }
 
 // TextTranslate Is single text translate
@@ -109,8 +186,52 @@ func (self *Deepl) TextTranslateWithParams(ctx context.Context, body *TextTransl
 	})
 }
 
-// All text translations end up calling the method
+// All text translations end up calling the method. The languages are validated and normalized, texts found
+// in config.Cache or config.Memory are not sent, texts that exceed the request limits of deepl are split
+// into several requests.
 func (self *Deepl) doTextTranslate(ctx context.Context, body *TextTranslateParams) ([]*TextResult, error) {
+	source, target, err := self.validateLanguages(body.SourceLang, body.TargetLang)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateFormality(body.Formality); err != nil {
+		return nil, err
+	}
+	if source != body.SourceLang || target != body.TargetLang {
+		params := *body
+		params.SourceLang, params.TargetLang = source, target
+		body = &params
+	}
+	if self.config.Cache != nil || self.config.Memory != nil {
+		return self.translateStored(ctx, body)
+	}
+	return self.translateUncached(ctx, body)
+}
+
+// translateUncached Sends the texts to deepl, the formality is only resolved here,
+// so that texts found in the cache never wait for the language catalog
+func (self *Deepl) translateUncached(ctx context.Context, body *TextTranslateParams) ([]*TextResult, error) {
+	formality, err := self.resolveFormality(ctx, body.Formality, body.TargetLang)
+	if err != nil {
+		return nil, err
+	}
+	if formality != body.Formality {
+		params := *body
+		params.Formality = formality
+		body = &params
+	}
+	batches := splitTexts(body.Text, maxTextsPerRequest, maxRequestBytes-self.requestOverhead(body))
+	if len(batches) > 1 {
+		return self.translateBatches(ctx, body, batches)
+	}
+	return self.translateTexts(ctx, body)
+}
+
+// translateTexts Sends the texts in a single translate request
+func (self *Deepl) translateTexts(ctx context.Context, body *TextTranslateParams) ([]*TextResult, error) {
+	if err := self.characterLimiter.wait(ctx, float64(countCharacters(body.Text))); err != nil {
+		return nil, err
+	}
 	request, err := self.createRequestWithJSON(ctx, textTranslateUri, http.MethodPost, body)
 	if err != nil {
 		return nil, err
@@ -206,6 +327,18 @@ func (self *Deepl) TextImprovementWithParams(ctx context.Context, body *TextImpr
 
 // All text improvement methods are finally called
 func (self *Deepl) doTextImprovement(ctx context.Context, body *TextImprovementParams) ([]*TextResult, error) {
+	_, target, err := self.validateLanguages("", body.TargetLang)
+	if err != nil {
+		return nil, err
+	}
+	if target != body.TargetLang {
+		params := *body
+		params.TargetLang = target
+		body = &params
+	}
+	if err := self.characterLimiter.wait(ctx, float64(countCharacters(body.Text))); err != nil {
+		return nil, err
+	}
 	request, err := self.createRequestWithJSON(ctx, textImprovementUri, http.MethodPost, body)
 	if err != nil {
 		return nil, err
@@ -240,99 +373,191 @@ func (self *Deepl) DocumentTranslateWithContext(ctx context.Context, document io
 	})
 }
 
+// DocumentTransWithParams Uploads the document with all params. The document is validated before it is sent:
+// its extension, its size if it can be determined, the output format and the formality,
+// an invalid document returns a *ValidationError.
 func (self *Deepl) DocumentTransWithParams(ctx context.Context, document io.Reader, filename string, body *DocumentTranslateParams) *CMD[DocumentResult] {
 	return NewCMD(ctx, func() (DocumentResult, error) {
 		return self.doDocumentTranslate(ctx, document, filename, body)
 	})
 }
 
+// DocumentTranslateWithOpener Uploads the document returned by open, which is called again to
+// reopen the document when the upload is retried
+func (self *Deepl) DocumentTranslateWithOpener(ctx context.Context, open DocumentOpener, filename string, body *DocumentTranslateParams) *CMD[DocumentResult] {
+	return NewCMD(ctx, func() (DocumentResult, error) {
+		return self.doDocumentUpload(ctx, open, filename, body)
+	})
+}
+
 // All document translate methods that are finally called
 // filename and body.Filename is used for the filename of the file field
 // in the form and the separate filename field in the form
 func (self *Deepl) doDocumentTranslate(ctx context.Context, document io.Reader, filename string, body *DocumentTranslateParams) (DocumentResult, error) {
+	return self.doDocumentUpload(ctx, readerOpener(document), filename, body)
+}
+
+// doDocumentUpload Streams the multipart form through a pipe, so the document is never held in memory.
+// Every attempt of the request reopens the document and writes the form with the same boundary,
+// the document is validated when it is opened for the first attempt.
+func (self *Deepl) doDocumentUpload(ctx context.Context, open DocumentOpener, filename string, body *DocumentTranslateParams) (DocumentResult, error) {
 	var result DocumentResult
-	buffer := bufferPool.Get().(*bytes.Buffer)
-	defer recycleBuffer(buffer)
-	writer := multipart.NewWriter(buffer)
-	part, err := writer.CreateFormFile("file", filename)
+	source, target, err := self.validateLanguages(body.SourceLang, body.TargetLang)
 	if err != nil {
 		return result, err
 	}
-	if _, err = io.Copy(part, document); err != nil {
+	formality, err := self.resolveFormality(ctx, body.Formality, target)
+	if err != nil {
 		return result, err
 	}
-	params := map[string]string{
-		"filename":      body.Filename,
-		"source_lang":   body.SourceLang,
-		"target_lang":   body.TargetLang,
-		"output_format": body.OutputFormat,
-		"formality":     body.Formality,
-		"glossary_id":   body.GlossaryId,
+	if source != body.SourceLang || target != body.TargetLang || formality != body.Formality {
+		params := *body
+		params.SourceLang, params.TargetLang, params.Formality = source, target, formality
+		body = &params
 	}
-	for k, v := range params {
-		if v == "" || strings.TrimSpace(v) == "" {
-			continue
+	fields := [][2]string{
+		{"filename", body.Filename},
+		{"source_lang", body.SourceLang},
+		{"target_lang", body.TargetLang},
+		{"output_format", body.OutputFormat},
+		{"formality", body.Formality},
+		{"glossary_id", body.GlossaryId},
+	}
+	contentType := multipart.NewWriter(io.Discard)
+	var written chan struct{}
+	getBody := func() (io.ReadCloser, error) {
+		if written != nil {
+			// the previous attempt must stop reading the document before it is reopened
+			<-written
 		}
-		if err = writer.WriteField(k, v); err != nil {
-			return result, err
+		document, err := open()
+		if err != nil {
+			return nil, err
+		}
+		if written == nil {
+			if err = validateDocument(filename, documentSize(document), body); err != nil {
+				document.Close()
+				return nil, err
+			}
 		}
+		reader, writer := io.Pipe()
+		written = make(chan struct{})
+		go func(written chan struct{}) {
+			defer close(written)
+			defer document.Close()
+			form := multipart.NewWriter(writer)
+			if err := form.SetBoundary(contentType.Boundary()); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			writer.CloseWithError(writeDocumentForm(form, document, filename, fields))
+		}(written)
+		return reader, nil
 	}
-	writer.Close()
-	request, err := self.createRequest(ctx, documentTranslateUri, http.MethodPost, writer.FormDataContentType(), buffer)
+	requestBody, err := getBody()
 	if err != nil {
 		return result, err
 	}
+	request, err := self.createRequest(ctx, documentTranslateUri, http.MethodPost, contentType.FormDataContentType(), requestBody)
+	if err != nil {
+		requestBody.Close()
+		return result, err
+	}
+	request.GetBody = getBody
 	err = self.doRequest(request, &result)
 	return result, err
 }
 
+func writeDocumentForm(form *multipart.Writer, document io.Reader, filename string, fields [][2]string) error {
+	for _, field := range fields {
+		if strings.TrimSpace(field[1]) == "" {
+			continue
+		}
+		if err := form.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(part, document); err != nil {
+		return err
+	}
+	return form.Close()
+}
+
 func (self *Deepl) CheckDocumentStatus(documentId, documentKey string) *CMD[CheckDocumentResult] {
 	return self.CheckDocumentStatusWithContext(context.Background(), documentId, documentKey)
 }
 
 func (self *Deepl) CheckDocumentStatusWithContext(ctx context.Context, documentId, documentKey string) *CMD[CheckDocumentResult] {
 	return NewCMD(ctx, func() (CheckDocumentResult, error) {
-		var result CheckDocumentResult
-		if err := self.validateDocumentIdAndKey(documentId, documentKey); err != nil {
-			return result, err
-		}
-		requestUri := fmt.Sprintf(checkDocumentStatusUri, documentId)
-		buffer := bufferPool.Get().(*bytes.Buffer)
-		defer recycleBuffer(buffer)
-		buffer.WriteString("{\"document_key\":\"" + documentKey + "\"}")
-		request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodPost, buffer)
-		if err != nil {
-			return result, err
-		}
-		err = self.doRequest(request, &result)
-		return result, err
+		return self.doCheckDocumentStatus(ctx, documentId, documentKey)
 	})
 }
 
+func (self *Deepl) doCheckDocumentStatus(ctx context.Context, documentId, documentKey string) (CheckDocumentResult, error) {
+	var result CheckDocumentResult
+	if err := validateDocumentIdAndKey(documentId, documentKey); err != nil {
+		return result, err
+	}
+	requestUri := fmt.Sprintf(checkDocumentStatusUri, documentId)
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	defer recycleBuffer(buffer)
+	buffer.WriteString("{\"document_key\":\"" + documentKey + "\"}")
+	request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodPost, buffer)
+	if err != nil {
+		return result, err
+	}
+	err = self.doRequest(request, &result)
+	return result, err
+}
+
 func (self *Deepl) DownloadDocument(documentId, documentKey string) *CMD[[]byte] {
 	return self.DownloadDocumentWithContext(context.Background(), documentId, documentKey)
 }
 
 func (self *Deepl) DownloadDocumentWithContext(ctx context.Context, documentId, documentKey string) *CMD[[]byte] {
 	return NewCMD(ctx, func() ([]byte, error) {
-		if err := self.validateDocumentIdAndKey(documentId, documentKey); err != nil {
-			return nil, err
-		}
-		requestUri := fmt.Sprintf(downloadDocumentsUri, documentId)
-		buffer := bufferPool.Get().(*bytes.Buffer)
-		defer recycleBuffer(buffer)
-		buffer.WriteString("{\"document_key\": \"" + documentKey + "\"}")
-		request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodPost, buffer)
-		if err != nil {
-			return nil, err
-		}
 		result := make([]byte, 0)
-		err = self.doRequest(request, &result)
+		err := self.doDownloadDocument(ctx, documentId, documentKey, &result)
 		return result, err
 	})
 }
 
-func (self *Deepl) validateDocumentIdAndKey(id, key string) error {
+// DownloadDocumentToWriter Is download the translated document directly into w without buffering it in memory
+func (self *Deepl) DownloadDocumentToWriter(documentId, documentKey string, w io.Writer) *CMD[int64] {
+	return self.DownloadDocumentToWriterWithContext(context.Background(), documentId, documentKey, w, nil)
+}
+
+// DownloadDocumentToWriterWithContext Copies the translated document into w, progress is called after
+// every write when it is not nil. Returns the number of bytes written.
+func (self *Deepl) DownloadDocumentToWriterWithContext(ctx context.Context, documentId, documentKey string, w io.Writer, progress ProgressFunc) *CMD[int64] {
+	return NewCMD(ctx, func() (int64, error) {
+		target := &downloadTarget{writer: w, progress: progress}
+		err := self.doDownloadDocument(ctx, documentId, documentKey, target)
+		return target.written, err
+	})
+}
+
+// doDownloadDocument Downloads the translated document into result, see handlerResponse for the supported types
+func (self *Deepl) doDownloadDocument(ctx context.Context, documentId, documentKey string, result any) error {
+	if err := validateDocumentIdAndKey(documentId, documentKey); err != nil {
+		return err
+	}
+	requestUri := fmt.Sprintf(downloadDocumentsUri, documentId)
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	defer recycleBuffer(buffer)
+	buffer.WriteString("{\"document_key\": \"" + documentKey + "\"}")
+	request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodPost, buffer)
+	if err != nil {
+		return err
+	}
+	return self.doRequest(request, result)
+}
+
+func validateDocumentIdAndKey(id, key string) error {
 	if !documentIdRegex.MatchString(id) {
 		return fmt.Errorf("the document-id format is incorrect, your document-id: %s", id)
 	}
@@ -367,16 +592,20 @@ func (self *Deepl) CreateGlossary(body *CreateGlossaryParams) *CMD[*GlossaryResu
 
 func (self *Deepl) CreateGlossaryWithContext(ctx context.Context, body *CreateGlossaryParams) *CMD[*GlossaryResult] {
 	return NewCMD(ctx, func() (*GlossaryResult, error) {
-		request, err := self.createRequestWithJSON(ctx, createGlossaryUri, http.MethodPost, body)
-		if err != nil {
-			return nil, err
-		}
-		result := &GlossaryResult{}
-		err = self.doRequest(request, result)
-		return result, err
+		return self.doCreateGlossary(ctx, body)
 	})
 }
 
+func (self *Deepl) doCreateGlossary(ctx context.Context, body *CreateGlossaryParams) (*GlossaryResult, error) {
+	request, err := self.createRequestWithJSON(ctx, createGlossaryUri, http.MethodPost, body)
+	if err != nil {
+		return nil, err
+	}
+	result := &GlossaryResult{}
+	err = self.doRequest(request, result)
+	return result, err
+}
+
 // ListGlossaries Is list all glossaries
 func (self *Deepl) ListGlossaries() *CMD[[]*GlossaryResult] {
 	return self.ListGlossariesWithContext(context.Background())
@@ -424,21 +653,25 @@ func (self *Deepl) GlossaryEntries(glossaryId, accept string) *CMD[string] {
 
 func (self *Deepl) GlossaryEntriesWithContext(ctx context.Context, glossaryId, accept string) *CMD[string] {
 	return NewCMD(ctx, func() (string, error) {
-		if !uuidRegex.MatchString(glossaryId) {
-			return "", fmt.Errorf("GlossaryId does not exist or is not formatted correctly, your glossaryId: %s ", glossaryId)
-		}
-		requestUri := fmt.Sprintf(glossaryEntriesUri, glossaryId)
-		request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodGet, nil)
-		if err != nil {
-			return "", err
-		}
-		request.Header.Set("Accept", accept)
-		result := make([]byte, 0)
-		err = self.doRequest(request, &result)
-		return string(result), err
+		return self.doGlossaryEntries(ctx, glossaryId, accept)
 	})
 }
 
+func (self *Deepl) doGlossaryEntries(ctx context.Context, glossaryId, accept string) (string, error) {
+	if !uuidRegex.MatchString(glossaryId) {
+		return "", fmt.Errorf("GlossaryId does not exist or is not formatted correctly, your glossaryId: %s ", glossaryId)
+	}
+	requestUri := fmt.Sprintf(glossaryEntriesUri, glossaryId)
+	request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodGet, nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Accept", accept)
+	result := make([]byte, 0)
+	err = self.doRequest(request, &result)
+	return string(result), err
+}
+
 func (self *Deepl) DeleteGlossary(glossaryId string) *CMD[struct{}] {
 	return self.DeleteGlossaryWithContext(context.Background(), glossaryId)
 }
@@ -458,16 +691,55 @@ func (self *Deepl) DeleteGlossaryWithContext(ctx context.Context, glossaryId str
 }
 
 // Send a request and deserialize the response Body through generics
+// Retryable statuses and transient network errors are retried according to config.Retry
 func (self *Deepl) doRequest(req *http.Request, result any) error {
-	response, err := self.client.Do(req)
+	policy := self.config.Retry
+	for attempt := 1; ; attempt++ {
+		retry, retryAfter, err := self.doAttempt(req, result)
+		if err == nil || !retry || attempt >= policy.MaxAttempts {
+			return err
+		}
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return err
+		}
+		if sleepErr := sleepWithContext(req.Context(), policy.delay(attempt, retryAfter)); sleepErr != nil {
+			// the rewound body may already hold a reopened document
+			if next.Body != nil {
+				next.Body.Close()
+			}
+			return err
+		}
+		req = next
+	}
+}
+
+// doAttempt Sends the request once and reports whether a failure may be retried
+func (self *Deepl) doAttempt(req *http.Request, result any) (bool, time.Duration, error) {
+	if req.Body != nil {
+		// the transport closes the body, but a middleware may answer without sending the request
+		defer req.Body.Close()
+	}
+	if err := self.requestLimiter.wait(req.Context(), 1); err != nil {
+		return false, 0, err
+	}
+	response, err := self.handler(req)
 	if err != nil {
-		return err
+		// a timeout of the http client is retried, unless the context of the request is done
+		return req.Context().Err() == nil && IsRetryable(err), 0, err
 	}
 	defer response.Body.Close()
+	if response.Request == nil {
+		response.Request = req
+	}
 	if err = self.handlerResponse(response, result); err != nil {
-		return err
+		var retryAfter time.Duration
+		if apiErr, ok := err.(*Error); ok {
+			retryAfter = apiErr.RetryAfter
+		}
+		return req.Context().Err() == nil && IsRetryable(err), retryAfter, err
 	}
-	return nil
+	return false, 0, nil
 }
 
 // Process the response. If the response code is not 200, return the corresponding error.
@@ -479,6 +751,9 @@ func (self *Deepl) handlerResponse(resp *http.Response, result any) error {
 		if result == nil {
 			return nil
 		}
+		if target, ok := result.(*downloadTarget); ok {
+			return target.copy(resp.Body, resp.ContentLength)
+		}
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return err
@@ -489,43 +764,46 @@ func (self *Deepl) handlerResponse(resp *http.Response, result any) error {
 		default:
 			return self.config.JSONDecode(body, result)
 		}
-	case 400:
-		body, err := io.ReadAll(resp.Body)
-		if err != nil || len(body) == 0 {
-			return ErrBadRequest
-		}
-		return NewError(400, string(body))
-	case 401:
-		return ErrAuthorization
-	case 403:
-		return ErrForbidden
-	case 404:
-		return ErrNotFount
-	case 413:
-		return ErrLimit
-	case 414:
-		return ErrLongURL
-	case 415:
-		return ErrNotAccept
-	case 429:
-		return ErrManyRequests
-	case 456:
-		return ErrQuotaExceeded
-	case 500:
-		return ErrInternal
-	case 503, 504:
-		return ErrResourceUnavailable
-	case 529:
-		return ErrManyRequests2
 	default:
-		return NewError(resp.StatusCode, "Unknown error")
+		return self.parseError(resp)
 	}
 	return nil
 }
 
+// Convert a non-2xx response into an *Error, keeping the message and detail sent by the
+// server, the request method and uri and the Retry-After header
+func (self *Deepl) parseError(resp *http.Response) error {
+	result := &Error{
+		Code:       resp.StatusCode,
+		Message:    "Unknown error",
+		RetryAfter: parseRetryAfter(resp.Header),
+	}
+	if sentinel, ok := sentinelErrors[resp.StatusCode].(*Error); ok {
+		result.Message = sentinel.Message
+	}
+	if resp.Request != nil {
+		result.Method = resp.Request.Method
+		result.URI = resp.Request.URL.RequestURI()
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		return result
+	}
+	payload := errorBody{}
+	if err = self.config.JSONDecode(body, &payload); err != nil {
+		result.Message = strings.TrimSpace(string(body))
+		return result
+	}
+	if payload.Message != "" {
+		result.Message = payload.Message
+	}
+	result.Detail = payload.Detail
+	return result
+}
+
 func (self *Deepl) createRequestWithJSON(ctx context.Context, uri, method string, body any) (*http.Request, error) {
 	switch v := body.(type) {
-	case types.Nil:
+	case nil:
 		return self.createRequest(ctx, uri, method, "application/json", nil)
 	case io.Reader:
 		return self.createRequest(ctx, uri, method, "application/json", v)
@@ -534,10 +812,8 @@ func (self *Deepl) createRequestWithJSON(ctx context.Context, uri, method string
 		if err != nil {
 			return nil, err
 		}
-		buffer := bufferPool.Get().(*bytes.Buffer)
-		defer recycleBuffer(buffer)
-		buffer.Write(encode)
-		return self.createRequest(ctx, uri, method, "application/json", buffer)
+		// the encoded body must outlive this call, so it is not backed by a pooled buffer
+		return self.createRequest(ctx, uri, method, "application/json", bytes.NewReader(encode))
 	}
 }
 
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -225,3 +227,125 @@ func TestDeepl_DeleteGlossaryWithContext(t *testing.T) {
 		log.Fatalln(err)
 	}
 }
+
+func TestNewDeepl_AccountType(t *testing.T) {
+	const freeKey = "00000000-0000-0000-0000-000000000000:fx"
+	const proKey = "00000000-0000-0000-0000-000000000000"
+	tests := []struct {
+		name        string
+		authKey     string
+		accountType int
+		want        int
+		wantErr     bool
+	}{
+		{"infer free", freeKey, 0, FreeAccount, false},
+		{"infer pro", proKey, 0, ProAccount, false},
+		{"explicit free", freeKey, FreeAccount, FreeAccount, false},
+		{"explicit pro", proKey, ProAccount, ProAccount, false},
+		{"free key with pro type", freeKey, ProAccount, 0, true},
+		{"pro key with free type", proKey, FreeAccount, 0, true},
+		{"unknown type", proKey, 3, 0, true},
+		{"malformed key", "<token>", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deepl, err := NewDeepl(Config{AuthKey: tt.authKey, AccountType: tt.accountType})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got account type %d", deepl.config.AccountType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deepl.config.AccountType != tt.want {
+				t.Fatalf("account type = %d, want %d", deepl.config.AccountType, tt.want)
+			}
+			wantHost := freeHost
+			if tt.want == ProAccount {
+				wantHost = proHost
+			}
+			if deepl.host != wantHost {
+				t.Fatalf("host = %s, want %s", deepl.host, wantHost)
+			}
+		})
+	}
+}
+
+// newTestDeepl Creates a client that sends every request to a local mock server
+func newTestDeepl(t *testing.T, handler http.HandlerFunc, configs ...func(*Config)) *Deepl {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config := Config{
+		AuthKey: "00000000-0000-0000-0000-000000000000:fx",
+		BaseURL: server.URL,
+	}
+	for _, fn := range configs {
+		fn(&config)
+	}
+	deepl, err := NewDeepl(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deepl
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestDeepl_BaseURL(t *testing.T) {
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != usageUri {
+			t.Errorf("path = %s, want %s", r.URL.Path, usageUri)
+		}
+		if r.Header.Get("Authorization") != "DeepL-Auth-Key 00000000-0000-0000-0000-000000000000:fx" {
+			t.Errorf("unexpected authorization header: %s", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"character_count":10,"character_limit":500000}`))
+	})
+	result, err := deepl.Usage().Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CharacterCount != 10 || result.CharacterLimit != 500000 {
+		t.Fatalf("unexpected usage: %+v", result)
+	}
+}
+
+func TestDeepl_Transport(t *testing.T) {
+	var calls int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"translations":[{"text":"你好"}]}`))
+	}, func(config *Config) {
+		config.Transport = transport
+	})
+	result, err := deepl.TextTranslate("hello", "ZH").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Text != "你好" || calls != 1 {
+		t.Fatalf("unexpected result %+v, transport calls %d", result, calls)
+	}
+
+	client := &http.Client{Transport: transport}
+	deepl = newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"character_count":1,"character_limit":2}`))
+	}, func(config *Config) {
+		config.HTTPClient = client
+	})
+	if deepl.client != client {
+		t.Fatal("custom http client is not used")
+	}
+	if _, err = deepl.Usage().Sync(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,338 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultManifestName Is the manifest file name used when DirectoryOptions.ManifestPath is not set
+const DefaultManifestName = ".deepl-manifest.json"
+
+// DefaultDirectoryConcurrency Is the number of documents translated at the same time when DirectoryOptions.Concurrency is not set
+const DefaultDirectoryConcurrency = 4
+
+// DirectoryOptions Controls a bulk translation of all documents in a directory
+type DirectoryOptions struct {
+	Params       DocumentTranslateParams // params of every document, TargetLang is replaced by every language of TargetLangs
+	TargetLangs  []string                // languages every document is translated into
+	OutputDir    string                  // directory of the translated documents mirroring the input tree, next to the inputs when empty
+	ManifestPath string                  // path of the manifest, DefaultManifestName in the root directory when empty
+	Concurrency  int                     // maximum number of documents translated at the same time, DefaultDirectoryConcurrency when unset
+	Extensions   []string                // document types to translate, e.g. "docx", all supported types when empty
+}
+
+// ManifestEntry Is the state of the translation of a single document into a single language
+type ManifestEntry struct {
+	Path             string    `json:"path"` // slash separated path relative to the root directory
+	TargetLang       string    `json:"target_lang"`
+	Output           string    `json:"output"`
+	Status           string    `json:"status"`
+	DocumentId       string    `json:"document_id,omitempty"`
+	DocumentKey      string    `json:"document_key,omitempty"`
+	BilledCharacters int       `json:"billed_characters,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Manifest Records the state of a bulk translation on disk. Uploaded documents are saved with their
+// id and key before waiting for them, so an interrupted run resumes polling and downloading them
+// instead of uploading and paying for them again.
+type Manifest struct {
+	mu      sync.Mutex
+	path    string
+	Entries map[string]*ManifestEntry `json:"entries"`
+}
+
+// LoadManifest Reads the manifest at path, a missing file results in an empty manifest
+func LoadManifest(path string) (*Manifest, error) {
+	manifest := &Manifest{
+		path:    path,
+		Entries: make(map[string]*ManifestEntry),
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("read manifest %s: %w", path, err)
+	}
+	if manifest.Entries == nil {
+		manifest.Entries = make(map[string]*ManifestEntry)
+	}
+	return manifest, nil
+}
+
+func manifestKey(path, targetLang string) string {
+	return path + "|" + targetLang
+}
+
+// entry Returns the entry of the document and language, creating a pending entry when there is none
+func (self *Manifest) entry(path, targetLang, output string) *ManifestEntry {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	key := manifestKey(path, targetLang)
+	entry, ok := self.Entries[key]
+	if !ok {
+		entry = &ManifestEntry{Path: path, TargetLang: targetLang, Status: ManifestStatusPending}
+		self.Entries[key] = entry
+	}
+	entry.Output = output
+	return entry
+}
+
+// update Applies fn to the entry and saves the manifest
+func (self *Manifest) update(entry *ManifestEntry, fn func(entry *ManifestEntry)) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	fn(entry)
+	entry.UpdatedAt = time.Now().UTC()
+	return self.save()
+}
+
+// save Writes the manifest into a temporary file that replaces the manifest, the lock must be held
+func (self *Manifest) save() error {
+	data, err := json.MarshalIndent(self, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(self.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(self.path), "."+filepath.Base(self.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), self.path)
+}
+
+// DirectoryError Is returned when some documents of a bulk translation failed,
+// the manifest holds the error of every failed entry unless ManifestErr is set
+type DirectoryError struct {
+	Failures    []*ManifestEntry
+	ManifestErr error // the first error saving the manifest, the manifest on disk may miss some errors
+}
+
+func (self *DirectoryError) Error() string {
+	messages := make([]string, len(self.Failures))
+	for i, entry := range self.Failures {
+		messages[i] = entry.Path + " (" + entry.TargetLang + "): " + entry.Error
+	}
+	message := fmt.Sprintf("%d documents failed: %s", len(self.Failures), strings.Join(messages, "; "))
+	if self.ManifestErr != nil {
+		message += "; save manifest: " + self.ManifestErr.Error()
+	}
+	return message
+}
+
+func (self *DirectoryError) Unwrap() error {
+	return self.ManifestErr
+}
+
+// TranslateDirectory Translates every supported document below root into every language of
+// options.TargetLangs. The progress is tracked in a manifest on disk, documents that are already
+// translated are skipped and documents that were uploaded by an interrupted run are only polled
+// and downloaded. Returns the manifest, and a *DirectoryError when some documents failed.
+func (self *Deepl) TranslateDirectory(ctx context.Context, root string, options DirectoryOptions) *CMD[*Manifest] {
+	return NewCMD(ctx, func() (*Manifest, error) {
+		return self.doTranslateDirectory(ctx, root, options)
+	})
+}
+
+func (self *Deepl) doTranslateDirectory(ctx context.Context, root string, options DirectoryOptions) (*Manifest, error) {
+	if len(options.TargetLangs) == 0 {
+		return nil, errors.New("no target language to translate the directory into")
+	}
+	if options.ManifestPath == "" {
+		options.ManifestPath = filepath.Join(root, DefaultManifestName)
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = DefaultDirectoryConcurrency
+	}
+	manifest, err := LoadManifest(options.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	files, err := self.directoryDocuments(root, manifest, options)
+	if err != nil {
+		return manifest, err
+	}
+	entries := make([]*ManifestEntry, 0, len(files)*len(options.TargetLangs))
+	for _, path := range files {
+		for _, targetLang := range options.TargetLangs {
+			params := options.Params
+			params.TargetLang = targetLang
+			name, _, err := self.outputDocumentName(filepath.Base(path), &params)
+			if err != nil {
+				return manifest, err
+			}
+			outputDir := root
+			if options.OutputDir != "" {
+				outputDir = options.OutputDir
+			}
+			output := filepath.Join(outputDir, filepath.Dir(filepath.FromSlash(path)), name)
+			entries = append(entries, manifest.entry(path, targetLang, output))
+		}
+	}
+	semaphore := make(chan struct{}, options.Concurrency)
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	directoryErr := &DirectoryError{}
+	for _, entry := range entries {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(entry *ManifestEntry) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			if err := self.translateDirectoryEntry(ctx, root, manifest, entry, options.Params); err != nil {
+				saveErr := manifest.update(entry, func(entry *ManifestEntry) {
+					entry.Error = err.Error()
+				})
+				mu.Lock()
+				if saveErr != nil && directoryErr.ManifestErr == nil {
+					directoryErr.ManifestErr = saveErr
+				}
+				mu.Unlock()
+			}
+		}(entry)
+	}
+	wg.Wait()
+	for _, entry := range entries {
+		if entry.Status != ManifestStatusDone {
+			directoryErr.Failures = append(directoryErr.Failures, entry)
+		}
+	}
+	if len(directoryErr.Failures) > 0 || directoryErr.ManifestErr != nil {
+		return manifest, directoryErr
+	}
+	return manifest, nil
+}
+
+// directoryDocuments Returns the slash separated paths of all documents below root, skipping hidden
+// files, the manifest and the translated documents recorded in the manifest
+func (self *Deepl) directoryDocuments(root string, manifest *Manifest, options DirectoryOptions) ([]string, error) {
+	skip := make(map[string]bool, len(manifest.Entries)+1)
+	skip[filepath.Clean(options.ManifestPath)] = true
+	for _, entry := range manifest.Entries {
+		skip[filepath.Clean(entry.Output)] = true
+	}
+	extensions := make(map[string]bool, len(options.Extensions))
+	for _, extension := range options.Extensions {
+		extensions[strings.ToLower(strings.TrimPrefix(extension, "."))] = true
+	}
+	outputDir := filepath.Clean(options.OutputDir)
+	files := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") || options.OutputDir != "" && path == outputDir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || skip[filepath.Clean(path)] {
+			return nil
+		}
+		format, err := documentFormat(d.Name())
+		if err != nil || len(extensions) > 0 && !extensions[format] {
+			return nil
+		}
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relative))
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// translateDirectoryEntry Brings a single entry to the status done. Uploaded entries are resumed,
+// when deepl no longer knows the document it is uploaded again.
+func (self *Deepl) translateDirectoryEntry(ctx context.Context, root string, manifest *Manifest, entry *ManifestEntry, params DocumentTranslateParams) error {
+	if entry.Status == ManifestStatusDone {
+		if _, err := os.Stat(entry.Output); err == nil {
+			return nil
+		}
+	}
+	if entry.Status == ManifestStatusUploaded && entry.DocumentId != "" {
+		err := self.finishDirectoryEntry(ctx, manifest, entry)
+		if !errors.Is(err, ErrNotFount) {
+			return err
+		}
+	}
+	path := filepath.Join(root, filepath.FromSlash(entry.Path))
+	open := func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}
+	params.TargetLang = entry.TargetLang
+	_, body, err := self.outputDocumentName(filepath.Base(path), &params)
+	if err != nil {
+		return err
+	}
+	uploaded, err := self.doDocumentUpload(ctx, open, filepath.Base(path), body)
+	if err != nil {
+		return err
+	}
+	err = manifest.update(entry, func(entry *ManifestEntry) {
+		entry.Status = ManifestStatusUploaded
+		entry.DocumentId = uploaded.DocumentId
+		entry.DocumentKey = uploaded.DocumentKey
+		entry.Error = ""
+	})
+	if err != nil {
+		return err
+	}
+	return self.finishDirectoryEntry(ctx, manifest, entry)
+}
+
+// finishDirectoryEntry Waits for an uploaded document and downloads it. Entries that failed on the
+// server are marked as failed and uploaded again by the next run, all other errors keep the entry
+// uploaded so that the next run resumes it.
+func (self *Deepl) finishDirectoryEntry(ctx context.Context, manifest *Manifest, entry *ManifestEntry) error {
+	document := DocumentResult{DocumentId: entry.DocumentId, DocumentKey: entry.DocumentKey}
+	status, err := self.waitAndDownloadFile(ctx, document, entry.Output)
+	documentErr := &DocumentError{}
+	if errors.As(err, &documentErr) {
+		saveErr := manifest.update(entry, func(entry *ManifestEntry) {
+			entry.Status = ManifestStatusFailed
+		})
+		if saveErr != nil {
+			return fmt.Errorf("%w, save manifest: %v", err, saveErr)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return manifest.update(entry, func(entry *ManifestEntry) {
+		entry.Status = ManifestStatusDone
+		entry.BilledCharacters = status.BilledCharacters
+		entry.Error = ""
+	})
+}
@@ -0,0 +1,189 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// multiDocumentServer Simulates the document endpoints for any number of documents
+type multiDocumentServer struct {
+	mu        sync.Mutex
+	documents map[string]string
+	uploads   int
+	fail      string // documents whose content contains fail end with the status error
+}
+
+func (self *multiDocumentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if r.URL.Path == documentTranslateUri {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		self.uploads++
+		id := fmt.Sprintf("%032d", self.uploads)
+		self.documents[id] = r.FormValue("target_lang") + ":" + string(data)
+		json.NewEncoder(w).Encode(DocumentResult{DocumentId: id, DocumentKey: strings.Repeat("K", 64)})
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/document/"), "/")
+	content, ok := self.documents[parts[0]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if len(parts) == 2 {
+		w.Write([]byte(content))
+		return
+	}
+	status := CheckDocumentResult{DocumentId: parts[0], Status: DocumentStatusDone, BilledCharacters: len(content)}
+	if self.fail != "" && strings.Contains(content, self.fail) {
+		status.Status = DocumentStatusError
+		status.ErrorMessage = "invalid document"
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDeepl_TranslateDirectory(t *testing.T) {
+	server := &multiDocumentServer{documents: make(map[string]string)}
+	deepl := newTestDeepl(t, server.ServeHTTP, func(config *Config) {
+		config.DocumentPollInterval = time.Millisecond
+	})
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"a.txt":         "alpha",
+		"sub/b.html":    "<p>beta</p>",
+		"archive.zip":   "ignored",
+		".hidden/c.txt": "ignored",
+	})
+	options := DirectoryOptions{TargetLangs: []string{"DE", "FR"}, Concurrency: 2}
+	manifest, err := deepl.TranslateDirectory(context.Background(), root, options).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.uploads != 4 || len(manifest.Entries) != 4 {
+		t.Fatalf("uploads = %d, entries = %d", server.uploads, len(manifest.Entries))
+	}
+	for name, want := range map[string]string{"a.de.txt": "DE:alpha", "a.fr.txt": "FR:alpha", "sub/b.de.html": "DE:<p>beta</p>"} {
+		if data, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(name))); string(data) != want {
+			t.Fatalf("%s = %q, want %q", name, data, want)
+		}
+	}
+	entry := manifest.Entries[manifestKey("sub/b.html", "FR")]
+	if entry == nil || entry.Status != ManifestStatusDone || entry.BilledCharacters == 0 {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	// a second run neither uploads the inputs again nor translates the outputs
+	if _, err = deepl.TranslateDirectory(context.Background(), root, options).Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if server.uploads != 4 {
+		t.Fatalf("second run uploaded documents again, uploads = %d", server.uploads)
+	}
+}
+
+func TestDeepl_TranslateDirectoryResume(t *testing.T) {
+	server := &multiDocumentServer{documents: map[string]string{fmt.Sprintf("%032d", 100): "DE:translated before"}}
+	deepl := newTestDeepl(t, server.ServeHTTP, func(config *Config) {
+		config.DocumentPollInterval = time.Millisecond
+	})
+	root, output := t.TempDir(), t.TempDir()
+	writeTestFiles(t, root, map[string]string{"a.txt": "alpha", "b.txt": "beta"})
+	manifestPath := filepath.Join(output, "manifest.json")
+	manifest, _ := LoadManifest(manifestPath)
+	manifest.Entries[manifestKey("a.txt", "DE")] = &ManifestEntry{
+		Path: "a.txt", TargetLang: "DE", Status: ManifestStatusUploaded,
+		DocumentId: fmt.Sprintf("%032d", 100), DocumentKey: strings.Repeat("K", 64),
+	}
+	manifest.Entries[manifestKey("b.txt", "DE")] = &ManifestEntry{
+		Path: "b.txt", TargetLang: "DE", Status: ManifestStatusUploaded,
+		DocumentId: fmt.Sprintf("%032d", 200), DocumentKey: strings.Repeat("K", 64),
+	}
+	manifest.save()
+
+	options := DirectoryOptions{TargetLangs: []string{"DE"}, OutputDir: output, ManifestPath: manifestPath}
+	manifest, err := deepl.TranslateDirectory(context.Background(), root, options).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(output, "a.de.txt")); string(data) != "DE:translated before" {
+		t.Fatalf("resumed document content %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(output, "b.de.txt")); string(data) != "DE:beta" {
+		t.Fatalf("expired document content %q", data)
+	}
+	if server.uploads != 1 {
+		t.Fatalf("uploads = %d, only the expired document must be uploaded again", server.uploads)
+	}
+	if entry := manifest.Entries[manifestKey("b.txt", "DE")]; entry.DocumentId != fmt.Sprintf("%032d", 1) {
+		t.Fatalf("manifest was not updated with the new upload: %+v", entry)
+	}
+}
+
+func TestDeepl_TranslateDirectoryFailure(t *testing.T) {
+	server := &multiDocumentServer{documents: make(map[string]string), fail: "broken"}
+	deepl := newTestDeepl(t, server.ServeHTTP, func(config *Config) {
+		config.DocumentPollInterval = time.Millisecond
+	})
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"good.txt": "good", "bad.txt": "broken"})
+	manifest, err := deepl.TranslateDirectory(context.Background(), root, DirectoryOptions{TargetLangs: []string{"DE"}}).Sync()
+	directoryErr := &DirectoryError{}
+	if !errors.As(err, &directoryErr) || len(directoryErr.Failures) != 1 || directoryErr.Failures[0].Path != "bad.txt" {
+		t.Fatalf("err = %v, want a failure of bad.txt", err)
+	}
+	if entry := manifest.Entries[manifestKey("bad.txt", "DE")]; entry.Status != ManifestStatusFailed || entry.Error == "" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	saved, err := LoadManifest(filepath.Join(root, DefaultManifestName))
+	if err != nil || len(saved.Entries) != 2 || saved.Entries[manifestKey("good.txt", "DE")].Status != ManifestStatusDone {
+		t.Fatalf("unexpected saved manifest %+v, %v", saved, err)
+	}
+}
+
+func TestDeepl_TranslateDirectoryManifestError(t *testing.T) {
+	server := &multiDocumentServer{documents: make(map[string]string)}
+	root, output := t.TempDir(), t.TempDir()
+	manifestDir := filepath.Join(output, "state")
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == documentTranslateUri {
+			// the manifest can no longer be saved once the document is uploaded
+			os.RemoveAll(manifestDir)
+			os.WriteFile(manifestDir, nil, 0o644)
+		}
+		server.ServeHTTP(w, r)
+	}, func(config *Config) {
+		config.DocumentPollInterval = time.Millisecond
+	})
+	writeTestFiles(t, root, map[string]string{"a.txt": "alpha"})
+	options := DirectoryOptions{TargetLangs: []string{"DE"}, OutputDir: output, ManifestPath: filepath.Join(manifestDir, "manifest.json")}
+	_, err := deepl.TranslateDirectory(context.Background(), root, options).Sync()
+	directoryErr := &DirectoryError{}
+	if !errors.As(err, &directoryErr) || directoryErr.ManifestErr == nil || !strings.Contains(err.Error(), "save manifest") {
+		t.Fatalf("err = %v, want the error saving the manifest", err)
+	}
+}
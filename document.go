@@ -0,0 +1,138 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DocumentError Is returned when deepl reports the status error for a document translation
+type DocumentError struct {
+	DocumentId string
+	Message    string
+}
+
+func (self *DocumentError) Error() string {
+	return "document translation failed, document_id: " + self.DocumentId + ", message: " + self.Message
+}
+
+// TranslateDocumentAndWait Uploads the document, waits until the translation is done and writes
+// the translated document to w. The returned result holds the final status of the translation.
+func (self *Deepl) TranslateDocumentAndWait(ctx context.Context, document io.Reader, filename string, body *DocumentTranslateParams, w io.Writer) *CMD[CheckDocumentResult] {
+	return NewCMD(ctx, func() (CheckDocumentResult, error) {
+		return self.translateDocumentAndWait(ctx, readerOpener(document), filename, body, w)
+	})
+}
+
+func (self *Deepl) translateDocumentAndWait(ctx context.Context, open DocumentOpener, filename string, body *DocumentTranslateParams, w io.Writer) (CheckDocumentResult, error) {
+	uploaded, err := self.doDocumentUpload(ctx, open, filename, body)
+	if err != nil {
+		return CheckDocumentResult{}, err
+	}
+	status, err := self.waitDocument(ctx, uploaded.DocumentId, uploaded.DocumentKey)
+	if err != nil {
+		return status, err
+	}
+	err = self.doDownloadDocument(ctx, uploaded.DocumentId, uploaded.DocumentKey, &downloadTarget{writer: w})
+	return status, err
+}
+
+// waitDocument Polls the status of the document until it is done or failed. The next poll is
+// scheduled after the seconds remaining reported by deepl, or after an exponentially growing
+// interval when there is no estimate, bounded by config.DocumentPollInterval and config.DocumentPollMaxInterval.
+func (self *Deepl) waitDocument(ctx context.Context, documentId, documentKey string) (CheckDocumentResult, error) {
+	interval := self.config.DocumentPollInterval
+	for {
+		status, err := self.doCheckDocumentStatus(ctx, documentId, documentKey)
+		if err != nil {
+			return status, err
+		}
+		switch status.Status {
+		case DocumentStatusDone:
+			return status, nil
+		case DocumentStatusError:
+			return status, &DocumentError{DocumentId: documentId, Message: status.ErrorMessage}
+		}
+		delay := interval
+		if status.SecondsRemaining > 0 {
+			delay = time.Duration(status.SecondsRemaining) * time.Second
+		} else {
+			interval *= 2
+		}
+		if delay < self.config.DocumentPollInterval {
+			delay = self.config.DocumentPollInterval
+		}
+		if delay > self.config.DocumentPollMaxInterval {
+			delay = self.config.DocumentPollMaxInterval
+		}
+		if interval > self.config.DocumentPollMaxInterval {
+			interval = self.config.DocumentPollMaxInterval
+		}
+		if err = sleepWithContext(ctx, delay); err != nil {
+			return status, err
+		}
+	}
+}
+
+// DocumentOpener Opens a document for uploading, the returned reader is closed after the upload
+type DocumentOpener func() (io.ReadCloser, error)
+
+// readerOpener Returns an opener that rewinds an io.ReadSeeker to its initial position on every call,
+// other readers can only be opened once, so their uploads are not retried
+func readerOpener(document io.Reader) DocumentOpener {
+	seeker, seekable := document.(io.ReadSeeker)
+	var offset int64 = -1
+	opened := false
+	return func() (io.ReadCloser, error) {
+		if seekable {
+			if offset < 0 {
+				position, err := seeker.Seek(0, io.SeekCurrent)
+				if err != nil {
+					return nil, err
+				}
+				offset = position
+			} else if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return nopReadCloser{seeker}, nil
+		}
+		if opened {
+			return nil, errors.New("document is not an io.ReadSeeker and can not be uploaded again")
+		}
+		opened = true
+		return nopReadCloser{document}, nil
+	}
+}
+
+// ProgressFunc Is called with the number of bytes written so far and the total size,
+// total is -1 when the size is unknown
+type ProgressFunc func(written, total int64)
+
+// downloadTarget Is a response result that copies the body into writer instead of reading it into memory
+type downloadTarget struct {
+	writer   io.Writer
+	progress ProgressFunc
+	written  int64
+	total    int64
+}
+
+func (self *downloadTarget) copy(body io.Reader, total int64) error {
+	self.total = total
+	_, err := io.Copy(self, body)
+	if err != nil && self.written > 0 {
+		// the error is not wrapped, so that a partially written download is never retried
+		return fmt.Errorf("download interrupted after %d bytes: %v", self.written, err)
+	}
+	return err
+}
+
+func (self *downloadTarget) Write(p []byte) (int, error) {
+	n, err := self.writer.Write(p)
+	self.written += int64(n)
+	if self.progress != nil && n > 0 {
+		self.progress(self.written, self.total)
+	}
+	return n, err
+}
@@ -0,0 +1,247 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	testDocumentId  = strings.Repeat("A", 32)
+	testDocumentKey = strings.Repeat("B", 64)
+)
+
+// documentServer Simulates the document endpoints, the translation is done after polls status checks
+type documentServer struct {
+	t          *testing.T
+	polls      int32
+	uploads    int32
+	checks     int32
+	failStatus string
+	content    string
+}
+
+func (self *documentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case documentTranslateUri:
+		atomic.AddInt32(&self.uploads, 1)
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			self.t.Error(err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		self.content = strings.ToUpper(string(data))
+		json.NewEncoder(w).Encode(DocumentResult{DocumentId: testDocumentId, DocumentKey: testDocumentKey})
+	case "/v2/document/" + testDocumentId:
+		check := atomic.AddInt32(&self.checks, 1)
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["document_key"] != testDocumentKey {
+			self.t.Errorf("unexpected document key %s", body["document_key"])
+		}
+		result := CheckDocumentResult{DocumentId: testDocumentId, Status: DocumentStatusTranslating}
+		if check > atomic.LoadInt32(&self.polls) {
+			result.Status = DocumentStatusDone
+			result.BilledCharacters = len(self.content)
+			if self.failStatus != "" {
+				result.Status = DocumentStatusError
+				result.ErrorMessage = self.failStatus
+			}
+		}
+		json.NewEncoder(w).Encode(result)
+	case "/v2/document/" + testDocumentId + "/result":
+		w.Write([]byte(self.content))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newDocumentTestDeepl(t *testing.T, server *documentServer) *Deepl {
+	server.t = t
+	return newTestDeepl(t, server.ServeHTTP, func(config *Config) {
+		config.DocumentPollInterval = time.Millisecond
+		config.DocumentPollMaxInterval = 5 * time.Millisecond
+	})
+}
+
+func TestDeepl_TranslateDocumentAndWait(t *testing.T) {
+	server := &documentServer{polls: 3}
+	deepl := newDocumentTestDeepl(t, server)
+	output := &bytes.Buffer{}
+	body := &DocumentTranslateParams{}
+	body.TargetLang = "DE"
+	status, err := deepl.TranslateDocumentAndWait(context.Background(), strings.NewReader("document"), "input.txt", body, output).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.String() != "DOCUMENT" || status.Status != DocumentStatusDone || status.BilledCharacters != 8 || server.checks != 4 {
+		t.Fatalf("unexpected status %+v, output %q after %d checks", status, output, server.checks)
+	}
+}
+
+func TestDeepl_TranslateDocumentAndWaitError(t *testing.T) {
+	server := &documentServer{polls: 1, failStatus: "Source and target language are equal."}
+	deepl := newDocumentTestDeepl(t, server)
+	body := &DocumentTranslateParams{}
+	_, err := deepl.TranslateDocumentAndWait(context.Background(), strings.NewReader("document"), "input.txt", body, io.Discard).Sync()
+	documentErr := &DocumentError{}
+	if !errors.As(err, &documentErr) || documentErr.Message != server.failStatus || documentErr.DocumentId != testDocumentId {
+		t.Fatalf("err = %v, want a DocumentError", err)
+	}
+}
+
+func TestDeepl_TranslateDocumentAndWaitCanceled(t *testing.T) {
+	server := &documentServer{polls: 1 << 20}
+	deepl := newDocumentTestDeepl(t, server)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	body := &DocumentTranslateParams{}
+	_, err := deepl.TranslateDocumentAndWait(ctx, strings.NewReader("document"), "input.txt", body, io.Discard).Sync()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+type onceReader struct {
+	io.Reader
+}
+
+func TestDeepl_DocumentTranslateStreaming(t *testing.T) {
+	var uploads int32
+	var contents []string
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != -1 {
+			t.Errorf("document upload is not streamed, content length %d", r.ContentLength)
+		}
+		if r.FormValue("target_lang") != "DE" {
+			t.Errorf("target_lang = %s", r.FormValue("target_lang"))
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		contents = append(contents, header.Filename+":"+string(data))
+		if atomic.AddInt32(&uploads, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(DocumentResult{DocumentId: testDocumentId, DocumentKey: testDocumentKey})
+	}, func(config *Config) {
+		config.Retry = testRetryPolicy
+	})
+
+	reader := strings.NewReader("skipped:document")
+	reader.Seek(int64(len("skipped:")), io.SeekStart)
+	if _, err := deepl.DocumentTranslate(reader, "input.txt", "DE").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(contents, ",") != "input.txt:document,input.txt:document" {
+		t.Fatalf("uploaded contents %q", contents)
+	}
+
+	uploads, contents = 0, nil
+	var opened int32
+	body := &DocumentTranslateParams{}
+	body.TargetLang = "DE"
+	open := func() (io.ReadCloser, error) {
+		atomic.AddInt32(&opened, 1)
+		return io.NopCloser(strings.NewReader("reopened")), nil
+	}
+	if _, err := deepl.DocumentTranslateWithOpener(context.Background(), open, "input.txt", body).Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if opened != 2 || len(contents) != 2 || contents[1] != "input.txt:reopened" {
+		t.Fatalf("opened %d times, uploaded contents %q", opened, contents)
+	}
+
+	uploads, contents = 0, nil
+	if _, err := deepl.DocumentTranslate(onceReader{strings.NewReader("once")}, "input.txt", "DE").Sync(); !errors.Is(err, ErrResourceUnavailable) {
+		t.Fatalf("err = %v, want %v", err, ErrResourceUnavailable)
+	}
+	if uploads != 1 {
+		t.Fatalf("a reader that can not be rewound was uploaded %d times", uploads)
+	}
+}
+
+func TestDeepl_DownloadDocumentToWriter(t *testing.T) {
+	content := strings.Repeat("translated document ", 4096)
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/document/"+testDocumentId+"/result" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write([]byte(content))
+	})
+	output := &bytes.Buffer{}
+	var calls int
+	var lastWritten, lastTotal int64
+	progress := func(written, total int64) {
+		calls++
+		lastWritten, lastTotal = written, total
+	}
+	written, err := deepl.DownloadDocumentToWriterWithContext(context.Background(), testDocumentId, testDocumentKey, output, progress).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(len(content)) || output.String() != content {
+		t.Fatalf("written %d bytes, output length %d", written, output.Len())
+	}
+	if calls == 0 || lastWritten != written || lastTotal != int64(len(content)) {
+		t.Fatalf("progress called %d times, last %d/%d", calls, lastWritten, lastTotal)
+	}
+	if _, err = deepl.DownloadDocumentToWriter("invalid", testDocumentKey, io.Discard).Sync(); err == nil {
+		t.Fatal("expected error for an invalid document id")
+	}
+}
+
+// countingCloser Counts how often the documents returned by an opener are closed
+type countingCloser struct {
+	io.Reader
+	closed *int32
+}
+
+func (self countingCloser) Close() error {
+	atomic.AddInt32(self.closed, 1)
+	return nil
+}
+
+func TestDeepl_DocumentTranslateRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		// cancel during the backoff before the next attempt
+		time.AfterFunc(20*time.Millisecond, cancel)
+	}, func(config *Config) {
+		config.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second}
+	})
+	var opened, closed int32
+	open := func() (io.ReadCloser, error) {
+		atomic.AddInt32(&opened, 1)
+		return countingCloser{Reader: strings.NewReader("document"), closed: &closed}, nil
+	}
+	body := &DocumentTranslateParams{}
+	body.TargetLang = "DE"
+	if _, err := deepl.DocumentTranslateWithOpener(ctx, open, "input.txt", body).Sync(); err == nil {
+		t.Fatal("a canceled upload succeeded")
+	}
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&closed) != atomic.LoadInt32(&opened) && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if opened, closed := atomic.LoadInt32(&opened), atomic.LoadInt32(&closed); opened != 2 || closed != opened {
+		t.Fatalf("opened %d documents, closed %d", opened, closed)
+	}
+}
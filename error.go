@@ -1,10 +1,23 @@
 package deepl
 
-import "strconv"
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
 
+// Error Is returned for every non-2xx response. Message and Detail hold the
+// message reported by the server, or the generic message of the status code
+// when the server did not send one.
 type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code       int           `json:"code"`
+	Message    string        `json:"message"`
+	Detail     string        `json:"detail,omitempty"`
+	Method     string        `json:"method,omitempty"`
+	URI        string        `json:"uri,omitempty"`
+	RetryAfter time.Duration `json:"retry_after,omitempty"` // parsed Retry-After response header
 }
 
 func NewError(code int, message string) error {
@@ -15,5 +28,91 @@ func NewError(code int, message string) error {
 }
 
 func (self Error) Error() string {
-	return "code: " + strconv.Itoa(self.Code) + ", message: " + self.Message
+	builder := strings.Builder{}
+	builder.WriteString("code: " + strconv.Itoa(self.Code) + ", message: " + self.Message)
+	if self.Detail != "" {
+		builder.WriteString(", detail: " + self.Detail)
+	}
+	if self.Method != "" || self.URI != "" {
+		builder.WriteString(", request: " + self.Method + " " + self.URI)
+	}
+	return builder.String()
+}
+
+// Is Reports whether the target is an *Error with the same status code, so that
+// errors.Is(err, ErrQuotaExceeded) matches every error parsed from a 456 response.
+// 503 and 504 both match ErrResourceUnavailable.
+func (self Error) Is(target error) bool {
+	var code int
+	switch t := target.(type) {
+	case *Error:
+		code = t.Code
+	case Error:
+		code = t.Code
+	default:
+		return false
+	}
+	return statusClass(self.Code) == statusClass(code)
+}
+
+func statusClass(code int) int {
+	if code == http.StatusServiceUnavailable {
+		return http.StatusGatewayTimeout
+	}
+	return code
+}
+
+// sentinelErrors Maps status codes to the exported errors that hold their generic message
+var sentinelErrors = map[int]error{
+	400: ErrBadRequest,
+	401: ErrAuthorization,
+	403: ErrForbidden,
+	404: ErrNotFount,
+	413: ErrLimit,
+	414: ErrLongURL,
+	415: ErrNotAccept,
+	429: ErrManyRequests,
+	456: ErrQuotaExceeded,
+	500: ErrInternal,
+	503: ErrResourceUnavailable,
+	504: ErrResourceUnavailable,
+	529: ErrManyRequests2,
+}
+
+// errorBody Is the json error body sent by the server
+type errorBody struct {
+	Message string `json:"message"`
+	Detail  string `json:"detail"`
+}
+
+// Temporary Reports whether the request failed because of throttling or a server-side
+// problem and may succeed when it is sent again later
+func (self Error) Temporary() bool {
+	return retryableStatus(self.Code)
+}
+
+// QuotaExhausted Reports whether the character quota of the account has been reached,
+// retrying does not help until the quota is reset or raised
+func (self Error) QuotaExhausted() bool {
+	return self.Code == 456
+}
+
+// AuthFailure Reports whether the auth key is invalid or lacks access to the resource
+func (self Error) AuthFailure() bool {
+	return self.Code == http.StatusUnauthorized || self.Code == http.StatusForbidden
+}
+
+// IsRetryable Reports whether a failed request may succeed when it is sent again.
+// Temporary api errors, network timeouts including the timeout of http.Client and
+// transient connection errors are retryable. The canceled or expired context of the
+// caller and all other errors are not.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	return transientError(err)
 }
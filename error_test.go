@@ -0,0 +1,163 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDeepl_parseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		header   map[string]string
+		body     string
+		sentinel error
+		want     Error
+	}{
+		{
+			name:     "json body",
+			status:   400,
+			body:     `{"message":"Value for 'target_lang' not supported.","detail":"target_lang"}`,
+			sentinel: ErrBadRequest,
+			want:     Error{Code: 400, Message: "Value for 'target_lang' not supported.", Detail: "target_lang", Method: http.MethodGet, URI: usageUri},
+		},
+		{
+			name:     "plain body",
+			status:   403,
+			body:     "Wrong endpoint",
+			sentinel: ErrForbidden,
+			want:     Error{Code: 403, Message: "Wrong endpoint", Method: http.MethodGet, URI: usageUri},
+		},
+		{
+			name:     "empty body",
+			status:   456,
+			sentinel: ErrQuotaExceeded,
+			want:     Error{Code: 456, Message: ErrQuotaExceeded.(*Error).Message, Method: http.MethodGet, URI: usageUri},
+		},
+		{
+			name:     "service unavailable",
+			status:   503,
+			header:   map[string]string{"Retry-After": "7"},
+			sentinel: ErrResourceUnavailable,
+			want:     Error{Code: 503, Message: ErrResourceUnavailable.(*Error).Message, Method: http.MethodGet, URI: usageUri, RetryAfter: 7 * time.Second},
+		},
+		{
+			name:   "unknown status",
+			status: 418,
+			want:   Error{Code: 418, Message: "Unknown error", Method: http.MethodGet, URI: usageUri},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}, func(config *Config) {
+				config.Retry = RetryPolicy{MaxAttempts: 1}
+			})
+			_, err := deepl.Usage().Sync()
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("err = %v, want *Error", err)
+			}
+			if *apiErr != tt.want {
+				t.Fatalf("err = %+v, want %+v", *apiErr, tt.want)
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Fatalf("errors.Is(%v, %v) = false", err, tt.sentinel)
+			}
+			if errors.Is(err, ErrNotFount) {
+				t.Fatalf("errors.Is(%v, ErrNotFount) = true", err)
+			}
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"too many requests", ErrManyRequests, true},
+		{"too many requests 529", &Error{Code: 529}, true},
+		{"service unavailable", &Error{Code: 503}, true},
+		{"gateway timeout", ErrResourceUnavailable, true},
+		{"internal", ErrInternal, true},
+		{"quota", ErrQuotaExceeded, false},
+		{"authorization", ErrAuthorization, false},
+		{"forbidden", ErrForbidden, false},
+		{"bad request", ErrBadRequest, false},
+		{"wrapped", fmt.Errorf("translate: %w", ErrManyRequests), true},
+		{"net timeout", &url.Error{Op: "Post", URL: "/", Err: timeoutError{}}, true},
+		{"connection reset", &url.Error{Op: "Post", URL: "/", Err: syscall.ECONNRESET}, true},
+		{"canceled", &url.Error{Op: "Post", URL: "/", Err: context.Canceled}, false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"wrapped deadline", &url.Error{Op: "Post", URL: "/", Err: fmt.Errorf("dial: %w", context.DeadlineExceeded)}, false},
+		{"other", errors.New("other"), false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: IsRetryable(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestIsRetryable_ClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+	client := &http.Client{Timeout: 10 * time.Millisecond}
+	_, err := client.Get(server.URL)
+	if err == nil || !IsRetryable(err) {
+		t.Fatalf("IsRetryable(%v) = false, want the http.Client timeout to be retryable", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	_, err = http.DefaultClient.Do(request)
+	if err == nil || IsRetryable(err) {
+		t.Fatalf("IsRetryable(%v) = true, want the expired context of the caller not to be retryable", err)
+	}
+}
+
+func TestError_Classification(t *testing.T) {
+	quota := ErrQuotaExceeded.(*Error)
+	if !quota.QuotaExhausted() || quota.Temporary() || quota.AuthFailure() {
+		t.Fatalf("unexpected classification of %v", quota)
+	}
+	for _, err := range []error{ErrAuthorization, ErrForbidden} {
+		apiErr := err.(*Error)
+		if !apiErr.AuthFailure() || apiErr.Temporary() || apiErr.QuotaExhausted() {
+			t.Fatalf("unexpected classification of %v", apiErr)
+		}
+	}
+	for _, err := range []error{ErrManyRequests, ErrManyRequests2, ErrResourceUnavailable} {
+		apiErr := err.(*Error)
+		if !apiErr.Temporary() || apiErr.AuthFailure() || apiErr.QuotaExhausted() {
+			t.Fatalf("unexpected classification of %v", apiErr)
+		}
+	}
+}
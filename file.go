@@ -0,0 +1,168 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// documentType Describes a document type deepl accepts
+type documentType struct {
+	outputs []string // the formats the document can be translated to, the first is the default
+	maxSize int64    // the maximum size of an uploaded document in bytes
+}
+
+func (self documentType) converts(format string) bool {
+	for _, output := range self.outputs {
+		if output == format {
+			return true
+		}
+	}
+	return false
+}
+
+// documentFormats Are the document types deepl accepts, keyed by the file extension
+var documentFormats = map[string]documentType{
+	"docx":  {outputs: []string{"docx", "pdf"}, maxSize: 30 << 20},
+	"doc":   {outputs: []string{"docx"}, maxSize: 30 << 20},
+	"pptx":  {outputs: []string{"pptx"}, maxSize: 30 << 20},
+	"xlsx":  {outputs: []string{"xlsx"}, maxSize: 30 << 20},
+	"pdf":   {outputs: []string{"pdf", "docx"}, maxSize: 30 << 20},
+	"htm":   {outputs: []string{"htm"}, maxSize: 5 << 20},
+	"html":  {outputs: []string{"html"}, maxSize: 5 << 20},
+	"txt":   {outputs: []string{"txt"}, maxSize: 1 << 20},
+	"xlf":   {outputs: []string{"xlf"}, maxSize: 10 << 20},
+	"xliff": {outputs: []string{"xliff"}, maxSize: 10 << 20},
+	"srt":   {outputs: []string{"srt"}, maxSize: 150 << 10},
+}
+
+// DocumentNamer Returns the file name of a translated document, name is the input file name
+// without its extension and format is the extension of the translated document
+type DocumentNamer func(name, targetLang, format string) string
+
+// DefaultDocumentNamer Names translated documents like report.de.docx
+func DefaultDocumentNamer(name, targetLang, format string) string {
+	return name + "." + strings.ToLower(targetLang) + "." + format
+}
+
+// documentFormat Returns the extension of the file name if deepl supports it
+func documentFormat(filename string) (string, error) {
+	format := strings.ToLower(strings.TrimPrefix(path.Ext(filename), "."))
+	if _, ok := documentFormats[format]; !ok {
+		return "", &ValidationError{Field: "filename", Value: filename, Message: "document type is not supported"}
+	}
+	return format, nil
+}
+
+// outputDocumentName Returns the file name of the translated document and the params of the translation,
+// the output format is inferred from the input when body.OutputFormat is not set
+func (self *Deepl) outputDocumentName(filename string, body *DocumentTranslateParams) (string, *DocumentTranslateParams, error) {
+	format, err := documentFormat(filename)
+	if err != nil {
+		return "", nil, err
+	}
+	params := *body
+	output := strings.ToLower(params.OutputFormat)
+	if output == "" {
+		output = documentFormats[format].outputs[0]
+		if output != format {
+			params.OutputFormat = output
+		}
+	}
+	name := strings.TrimSuffix(filename, path.Ext(filename))
+	return self.config.DocumentNamer(name, params.TargetLang, output), &params, nil
+}
+
+// DocumentTranslateFile Is translate the file and write the translated document next to it,
+// returns the path of the translated document
+func (self *Deepl) DocumentTranslateFile(file, target string) *CMD[string] {
+	body := &DocumentTranslateParams{}
+	body.TargetLang = target
+	return self.DocumentTranslateFileWithParams(context.Background(), file, body)
+}
+
+// DocumentTranslateFileWithParams Uploads the file, waits for the translation and writes the
+// translated document next to the file, named by config.DocumentNamer
+func (self *Deepl) DocumentTranslateFileWithParams(ctx context.Context, file string, body *DocumentTranslateParams) *CMD[string] {
+	return NewCMD(ctx, func() (string, error) {
+		name, params, err := self.outputDocumentName(filepath.Base(file), body)
+		if err != nil {
+			return "", err
+		}
+		open := func() (io.ReadCloser, error) {
+			return os.Open(file)
+		}
+		output := filepath.Join(filepath.Dir(file), name)
+		return output, self.translateFileTo(ctx, open, filepath.Base(file), params, output)
+	})
+}
+
+// DocumentTranslateFS Translates the file name of fsys and writes the translated document into outputDir,
+// keeping the directory of name. Returns the path of the translated document.
+func (self *Deepl) DocumentTranslateFS(ctx context.Context, fsys fs.FS, name string, body *DocumentTranslateParams, outputDir string) *CMD[string] {
+	return NewCMD(ctx, func() (string, error) {
+		outputName, params, err := self.outputDocumentName(path.Base(name), body)
+		if err != nil {
+			return "", err
+		}
+		open := func() (io.ReadCloser, error) {
+			return fsys.Open(name)
+		}
+		output := filepath.Join(outputDir, filepath.FromSlash(path.Dir(name)), outputName)
+		return output, self.translateFileTo(ctx, open, path.Base(name), params, output)
+	})
+}
+
+// translateFileTo Translates the document and writes it to output
+func (self *Deepl) translateFileTo(ctx context.Context, open DocumentOpener, filename string, body *DocumentTranslateParams, output string) error {
+	uploaded, err := self.doDocumentUpload(ctx, open, filename, body)
+	if err != nil {
+		return err
+	}
+	_, err = self.waitAndDownloadFile(ctx, uploaded, output)
+	return err
+}
+
+// waitAndDownloadFile Waits for the translation of the document and writes it to output. The document is
+// downloaded into a temporary file that replaces output once it is complete, so output is never left half-written.
+func (self *Deepl) waitAndDownloadFile(ctx context.Context, document DocumentResult, output string) (CheckDocumentResult, error) {
+	status, err := self.waitDocument(ctx, document.DocumentId, document.DocumentKey)
+	if err != nil {
+		return status, err
+	}
+	if err = os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return status, err
+	}
+	file, err := createTempFile(output)
+	if err != nil {
+		return status, err
+	}
+	defer os.Remove(file.Name())
+	err = self.doDownloadDocument(ctx, document.DocumentId, document.DocumentKey, &downloadTarget{writer: file})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return status, err
+	}
+	return status, os.Rename(file.Name(), output)
+}
+
+// createTempFile Creates a temporary file next to output. Unlike os.CreateTemp, which creates files with mode 0600,
+// the file is created with mode 0644 masked by the umask, so that output gets the mode of any other new file.
+func createTempFile(output string) (*os.File, error) {
+	for {
+		name := filepath.Join(filepath.Dir(output), "."+filepath.Base(output)+"."+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, fs.ErrExist) {
+			return file, err
+		}
+	}
+}
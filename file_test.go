@@ -0,0 +1,93 @@
+package deepl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDeepl_outputDocumentName(t *testing.T) {
+	deepl, _ := NewDeepl(Config{AuthKey: "00000000-0000-0000-0000-000000000000:fx"})
+	tests := []struct {
+		filename     string
+		outputFormat string
+		wantName     string
+		wantFormat   string
+		wantErr      bool
+	}{
+		{"report.docx", "", "report.de.docx", "", false},
+		{"Slides.PPTX", "", "Slides.de.pptx", "", false},
+		{"legacy.doc", "", "legacy.de.docx", "docx", false},
+		{"scan.pdf", "docx", "scan.de.docx", "docx", false},
+		{"archive.zip", "", "", "", true},
+		{"noextension", "", "", "", true},
+	}
+	for _, tt := range tests {
+		body := &DocumentTranslateParams{OutputFormat: tt.outputFormat}
+		body.TargetLang = "DE"
+		name, params, err := deepl.outputDocumentName(tt.filename, body)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.filename)
+			}
+			continue
+		}
+		if err != nil || name != tt.wantName || params.OutputFormat != tt.wantFormat {
+			t.Errorf("%s: got %s, %q, %v, want %s, %q", tt.filename, name, params.OutputFormat, err, tt.wantName, tt.wantFormat)
+		}
+	}
+}
+
+func TestDeepl_DocumentTranslateFile(t *testing.T) {
+	server := &documentServer{polls: 1}
+	deepl := newDocumentTestDeepl(t, server)
+	dir := t.TempDir()
+	input := filepath.Join(dir, "readme.txt")
+	os.WriteFile(input, []byte("hello"), 0o644)
+	output, err := deepl.DocumentTranslateFile(input, "DE").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != filepath.Join(dir, "readme.de.txt") {
+		t.Fatalf("output = %s", output)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "HELLO" {
+		t.Fatalf("output content %q", data)
+	}
+	// the output gets the mode of any other new file, which the input was created with
+	inputInfo, _ := os.Stat(input)
+	if outputInfo, err := os.Stat(output); err != nil || outputInfo.Mode() != inputInfo.Mode() {
+		t.Fatalf("output mode %v, want %v", outputInfo.Mode(), inputInfo.Mode())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Fatalf("temporary files were left behind: %v", entries)
+	}
+
+	if _, err = deepl.DocumentTranslateFile(filepath.Join(dir, "image.gif"), "DE").Sync(); err == nil {
+		t.Fatal("expected error for an unsupported document type")
+	}
+	if server.uploads != 1 {
+		t.Fatalf("uploads = %d, want 1", server.uploads)
+	}
+}
+
+func TestDeepl_DocumentTranslateFS(t *testing.T) {
+	server := &documentServer{}
+	deepl := newDocumentTestDeepl(t, server)
+	fsys := fstest.MapFS{"docs/guide/index.html": &fstest.MapFile{Data: []byte("<p>guide</p>")}}
+	outputDir := t.TempDir()
+	body := &DocumentTranslateParams{}
+	body.TargetLang = "PT-BR"
+	output, err := deepl.DocumentTranslateFS(context.Background(), fsys, "docs/guide/index.html", body, outputDir).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != filepath.Join(outputDir, "docs", "guide", "index.pt-br.html") {
+		t.Fatalf("output = %s", output)
+	}
+	if data, _ := os.ReadFile(output); string(data) != "<P>GUIDE</P>" {
+		t.Fatalf("output content %q", data)
+	}
+}
@@ -0,0 +1,302 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GlossaryEntry Is a source term and its translation
+type GlossaryEntry struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// GlossaryEntries Are the entries of a glossary in their original order
+type GlossaryEntries []GlossaryEntry
+
+// ParseGlossaryTSV Parses entries in the tsv format of deepl, one entry per line with the source
+// and target term separated by a tab. Empty lines are skipped and terms are trimmed.
+func ParseGlossaryTSV(data string) (GlossaryEntries, error) {
+	entries := make(GlossaryEntries, 0)
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		terms := strings.Split(line, "\t")
+		if len(terms) != 2 {
+			return nil, &ValidationError{Field: "entries", Value: line, Message: "line " + strconv.Itoa(i+1) + ": expected a source and a target term separated by a tab"}
+		}
+		entries = append(entries, GlossaryEntry{Source: strings.TrimSpace(terms[0]), Target: strings.TrimSpace(terms[1])})
+	}
+	if err := entries.Validate(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseGlossaryCSV Parses entries in the csv format of deepl, one entry per record with the source and
+// the target term, optionally followed by the source and target language which are ignored
+func ParseGlossaryCSV(data string) (GlossaryEntries, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+	entries := make(GlossaryEntries, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) != 2 && len(record) != 4 {
+			line, _ := reader.FieldPos(0)
+			return nil, &ValidationError{Field: "entries", Value: strings.Join(record, ","), Message: "line " + strconv.Itoa(line) + ": expected a source and a target term and optionally their languages"}
+		}
+		entries = append(entries, GlossaryEntry{Source: strings.TrimSpace(record[0]), Target: strings.TrimSpace(record[1])})
+	}
+	if err := entries.Validate(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Validate Checks that every term is trimmed, not empty and contains no tab or line break,
+// and that no source term occurs twice
+func (self GlossaryEntries) Validate() error {
+	sources := make(map[string]int, len(self))
+	for i, entry := range self {
+		for _, term := range []string{entry.Source, entry.Target} {
+			message := ""
+			switch {
+			case term == "":
+				message = "terms must not be empty"
+			case strings.ContainsAny(term, "\t\r\n"):
+				message = "terms must not contain tabs or line breaks"
+			case strings.TrimSpace(term) != term:
+				message = "terms must not start or end with whitespace"
+			}
+			if message != "" {
+				return &ValidationError{Field: "entries", Value: term, Message: "entry " + strconv.Itoa(i+1) + ": " + message}
+			}
+		}
+		if first, ok := sources[entry.Source]; ok {
+			return &ValidationError{Field: "entries", Value: entry.Source, Message: "entry " + strconv.Itoa(i+1) + ": duplicate of the source term of entry " + strconv.Itoa(first+1)}
+		}
+		sources[entry.Source] = i
+	}
+	return nil
+}
+
+// FormatTSV Returns the entries in the tsv format of deepl
+func (self GlossaryEntries) FormatTSV() string {
+	builder := strings.Builder{}
+	for _, entry := range self {
+		builder.WriteString(entry.Source + "\t" + entry.Target + "\n")
+	}
+	return builder.String()
+}
+
+// FormatCSV Returns the entries in the csv format of deepl
+func (self GlossaryEntries) FormatCSV() string {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	for _, entry := range self {
+		writer.Write([]string{entry.Source, entry.Target})
+	}
+	writer.Flush()
+	return buffer.String()
+}
+
+// CreateGlossaryFromEntries Is create a glossary from validated entries
+func (self *Deepl) CreateGlossaryFromEntries(name, source, target string, entries GlossaryEntries) *CMD[*GlossaryResult] {
+	return self.CreateGlossaryFromEntriesWithContext(context.Background(), name, source, target, entries)
+}
+
+func (self *Deepl) CreateGlossaryFromEntriesWithContext(ctx context.Context, name, source, target string, entries GlossaryEntries) *CMD[*GlossaryResult] {
+	return NewCMD(ctx, func() (*GlossaryResult, error) {
+		if err := entries.Validate(); err != nil {
+			return nil, err
+		}
+		body := AcquireCreateGlossaryParams()
+		defer RecycleParams(body)
+		body.Name = name
+		body.SourceLang = source
+		body.TargetLang = target
+		body.Entries = entries.FormatTSV()
+		body.EntriesFormat = EntriesFormatTSV
+		return self.doCreateGlossary(ctx, body)
+	})
+}
+
+// GetGlossaryEntries Is retrieve the parsed glossary entries
+func (self *Deepl) GetGlossaryEntries(glossaryId string) *CMD[GlossaryEntries] {
+	return self.GetGlossaryEntriesWithContext(context.Background(), glossaryId)
+}
+
+func (self *Deepl) GetGlossaryEntriesWithContext(ctx context.Context, glossaryId string) *CMD[GlossaryEntries] {
+	return NewCMD(ctx, func() (GlossaryEntries, error) {
+		data, err := self.doGlossaryEntries(ctx, glossaryId, EntriesAcceptTSV)
+		if err != nil {
+			return nil, err
+		}
+		return ParseGlossaryTSV(data)
+	})
+}
+
+// GlossaryChange Is a source term whose translation changed
+type GlossaryChange struct {
+	Source    string
+	OldTarget string
+	NewTarget string
+}
+
+// GlossaryDiff Is the difference between the entries of a glossary and the wanted entries
+type GlossaryDiff struct {
+	Added   GlossaryEntries
+	Removed GlossaryEntries
+	Changed []GlossaryChange
+}
+
+// DiffGlossaryEntries Compares the current entries of a glossary with the wanted entries, matching them by source term
+func DiffGlossaryEntries(current, wanted GlossaryEntries) GlossaryDiff {
+	diff := GlossaryDiff{}
+	targets := make(map[string]string, len(current))
+	for _, entry := range current {
+		targets[entry.Source] = entry.Target
+	}
+	sources := make(map[string]bool, len(wanted))
+	for _, entry := range wanted {
+		sources[entry.Source] = true
+		target, ok := targets[entry.Source]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, entry)
+		case target != entry.Target:
+			diff.Changed = append(diff.Changed, GlossaryChange{Source: entry.Source, OldTarget: target, NewTarget: entry.Target})
+		}
+	}
+	for _, entry := range current {
+		if !sources[entry.Source] {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+	return diff
+}
+
+// Empty Reports whether the entries are equal
+func (self GlossaryDiff) Empty() bool {
+	return len(self.Added) == 0 && len(self.Removed) == 0 && len(self.Changed) == 0
+}
+
+func (self GlossaryDiff) String() string {
+	return fmt.Sprintf("%d added, %d removed, %d changed", len(self.Added), len(self.Removed), len(self.Changed))
+}
+
+// SyncGlossaryOptions Are the options of SyncGlossary
+type SyncGlossaryOptions struct {
+	DryRun bool      // only print the plan, no glossary is created or deleted
+	Output io.Writer // the plan is printed to Output, os.Stdout is used for dry runs when unset
+}
+
+// SyncGlossaryResult Is the outcome of SyncGlossary
+type SyncGlossaryResult struct {
+	GlossaryId         string // the glossary with the wanted entries, the current glossary on dry runs
+	PreviousGlossaryId string // the glossary that was replaced, empty when there was none
+	Created            bool   // whether a new glossary was created, or would be created on dry runs
+	Diff               GlossaryDiff
+}
+
+// SyncGlossary Makes the glossary name of the language pair contain exactly entries. Glossaries can
+// not be edited, so when the entries differ a new glossary is created and the previous one is deleted
+// afterwards, when they are equal nothing is changed.
+func (self *Deepl) SyncGlossary(ctx context.Context, name, source, target string, entries GlossaryEntries, options SyncGlossaryOptions) *CMD[*SyncGlossaryResult] {
+	return NewCMD(ctx, func() (*SyncGlossaryResult, error) {
+		return self.doSyncGlossary(ctx, name, source, target, entries, options)
+	})
+}
+
+func (self *Deepl) doSyncGlossary(ctx context.Context, name, source, target string, entries GlossaryEntries, options SyncGlossaryOptions) (*SyncGlossaryResult, error) {
+	if err := entries.Validate(); err != nil {
+		return nil, err
+	}
+	output := options.Output
+	if output == nil {
+		output = io.Discard
+		if options.DryRun {
+			output = os.Stdout
+		}
+	}
+	glossaries, err := self.ListGlossariesWithContext(ctx).Sync()
+	if err != nil {
+		return nil, err
+	}
+	var previous *GlossaryResult
+	for _, glossary := range glossaries {
+		if glossary.Name != name || !sameGlossaryLanguage(glossary.SourceLang, source) || !sameGlossaryLanguage(glossary.TargetLang, target) {
+			continue
+		}
+		// the most recently created glossary is the current one
+		if previous == nil || glossary.CreationTime > previous.CreationTime {
+			previous = glossary
+		}
+	}
+	result := &SyncGlossaryResult{}
+	current := GlossaryEntries{}
+	if previous != nil {
+		result.GlossaryId = previous.GlossaryId
+		if current, err = self.GetGlossaryEntriesWithContext(ctx, previous.GlossaryId).Sync(); err != nil {
+			return nil, err
+		}
+	}
+	result.Diff = DiffGlossaryEntries(current, entries)
+	if previous != nil && result.Diff.Empty() {
+		fmt.Fprintf(output, "glossary %s (%s -> %s) is up to date: %s\n", name, source, target, previous.GlossaryId)
+		return result, nil
+	}
+	result.Created = true
+	if previous != nil {
+		result.PreviousGlossaryId = previous.GlossaryId
+	}
+	printGlossaryPlan(output, name, source, target, len(entries), previous, result.Diff)
+	if options.DryRun {
+		return result, nil
+	}
+	created, err := self.CreateGlossaryFromEntriesWithContext(ctx, name, source, target, entries).Sync()
+	if err != nil {
+		return nil, err
+	}
+	result.GlossaryId = created.GlossaryId
+	if previous != nil {
+		if _, err = self.DeleteGlossaryWithContext(ctx, previous.GlossaryId).Sync(); err != nil {
+			return result, fmt.Errorf("glossary %s was created, but the previous glossary %s could not be deleted: %w", created.GlossaryId, previous.GlossaryId, err)
+		}
+	}
+	return result, nil
+}
+
+func printGlossaryPlan(output io.Writer, name, source, target string, count int, previous *GlossaryResult, diff GlossaryDiff) {
+	fmt.Fprintf(output, "create glossary %s (%s -> %s) with %d entries: %s\n", name, source, target, count, diff)
+	for _, entry := range diff.Added {
+		fmt.Fprintf(output, "+ %s\t%s\n", entry.Source, entry.Target)
+	}
+	for _, entry := range diff.Removed {
+		fmt.Fprintf(output, "- %s\t%s\n", entry.Source, entry.Target)
+	}
+	for _, change := range diff.Changed {
+		fmt.Fprintf(output, "~ %s\t%s -> %s\n", change.Source, change.OldTarget, change.NewTarget)
+	}
+	if previous != nil {
+		fmt.Fprintf(output, "delete glossary %s\n", previous.GlossaryId)
+	}
+}
+
+func sameGlossaryLanguage(a, b string) bool {
+	return NormalizeLanguage(a).Base() == NormalizeLanguage(b).Base()
+}
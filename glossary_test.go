@@ -0,0 +1,185 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testGlossaryId = "0f4e5c1a-2b3d-4e5f-8a9b-0c1d2e3f4a5b"
+
+func TestParseGlossaryTSV(t *testing.T) {
+	entries, err := ParseGlossaryTSV("Hello\tHallo\r\n\n  world \t Welt\n")
+	want := GlossaryEntries{{Source: "Hello", Target: "Hallo"}, {Source: "world", Target: "Welt"}}
+	if err != nil || !reflect.DeepEqual(entries, want) {
+		t.Fatalf("entries %v, err = %v", entries, err)
+	}
+	if formatted := entries.FormatTSV(); formatted != "Hello\tHallo\nworld\tWelt\n" {
+		t.Fatalf("FormatTSV() = %q", formatted)
+	}
+	for _, data := range []string{"Hello\tHallo\nmissing target", "a\tb\tc", "Hello\tHallo\nHello\tServus", "Hello\t "} {
+		validationErr := &ValidationError{}
+		if _, err = ParseGlossaryTSV(data); !errors.As(err, &validationErr) {
+			t.Errorf("%q: err = %v, want a ValidationError", data, err)
+		}
+	}
+}
+
+func TestParseGlossaryCSV(t *testing.T) {
+	entries, err := ParseGlossaryCSV("Hello,Hallo\n\"Good, morning\",Guten Morgen,en,de\n")
+	want := GlossaryEntries{{Source: "Hello", Target: "Hallo"}, {Source: "Good, morning", Target: "Guten Morgen"}}
+	if err != nil || !reflect.DeepEqual(entries, want) {
+		t.Fatalf("entries %v, err = %v", entries, err)
+	}
+	if formatted := entries.FormatCSV(); formatted != "Hello,Hallo\n\"Good, morning\",Guten Morgen\n" {
+		t.Fatalf("FormatCSV() = %q", formatted)
+	}
+	validationErr := &ValidationError{}
+	if _, err = ParseGlossaryCSV("Hello,Hallo,en\n"); !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want a ValidationError", err)
+	}
+}
+
+func TestGlossaryEntries_Validate(t *testing.T) {
+	tests := []struct {
+		entries GlossaryEntries
+		valid   bool
+	}{
+		{GlossaryEntries{{Source: "a", Target: "b"}, {Source: "c", Target: "d"}}, true},
+		{GlossaryEntries{}, true},
+		{GlossaryEntries{{Source: "", Target: "b"}}, false},
+		{GlossaryEntries{{Source: "a", Target: "b\nc"}}, false},
+		{GlossaryEntries{{Source: "a\tb", Target: "c"}}, false},
+		{GlossaryEntries{{Source: " a", Target: "b"}}, false},
+		{GlossaryEntries{{Source: "a", Target: "b"}, {Source: "a", Target: "c"}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.entries.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%v: err = %v", tt.entries, err)
+		}
+	}
+}
+
+func TestDeepl_GlossaryEntriesTyped(t *testing.T) {
+	var created CreateGlossaryParams
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case createGlossaryUri:
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(GlossaryResult{GlossaryId: testGlossaryId, Name: created.Name, EntryCount: 2})
+		case "/v2/glossaries/" + testGlossaryId + "/entries":
+			if accept := r.Header.Get("Accept"); accept != EntriesAcceptTSV {
+				t.Errorf("Accept = %s", accept)
+			}
+			w.Write([]byte(created.Entries))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	entries := GlossaryEntries{{Source: "Save", Target: "Speichern"}, {Source: "Open", Target: "Öffnen"}}
+	result, err := deepl.CreateGlossaryFromEntries("ui", "EN", "DE", entries).Sync()
+	if err != nil || result.GlossaryId != testGlossaryId {
+		t.Fatalf("result %+v, err = %v", result, err)
+	}
+	if created.EntriesFormat != EntriesFormatTSV || created.Entries != "Save\tSpeichern\nOpen\tÖffnen\n" {
+		t.Fatalf("created %+v", created)
+	}
+	fetched, err := deepl.GetGlossaryEntries(testGlossaryId).Sync()
+	if err != nil || !reflect.DeepEqual(fetched, entries) {
+		t.Fatalf("fetched %v, err = %v", fetched, err)
+	}
+	invalid := GlossaryEntries{{Source: "Save", Target: ""}}
+	validationErr := &ValidationError{}
+	if _, err = deepl.CreateGlossaryFromEntries("ui", "EN", "DE", invalid).Sync(); !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want a ValidationError", err)
+	}
+}
+
+// glossaryServer Simulates the v2 glossary endpoints, glossaries are numbered in the order they are created
+type glossaryServer struct {
+	t          *testing.T
+	glossaries map[string]*GlossaryResult
+	entries    map[string]string
+	created    int
+	deleted    []string
+}
+
+func (self *glossaryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v2/glossaries/"), "/entries")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == listGlossariesUri:
+		result := GlossariesOptional{Glossaries: []*GlossaryResult{}}
+		for _, glossary := range self.glossaries {
+			result.Glossaries = append(result.Glossaries, glossary)
+		}
+		json.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPost && r.URL.Path == createGlossaryUri:
+		body := CreateGlossaryParams{}
+		json.NewDecoder(r.Body).Decode(&body)
+		self.created++
+		glossary := &GlossaryResult{
+			GlossaryId:   fmt.Sprintf("00000000-0000-0000-0000-%012d", self.created),
+			Name:         body.Name,
+			SourceLang:   strings.ToLower(body.SourceLang),
+			TargetLang:   strings.ToLower(body.TargetLang),
+			CreationTime: fmt.Sprintf("2026-01-01T00:00:%02dZ", self.created),
+		}
+		self.glossaries[glossary.GlossaryId] = glossary
+		self.entries[glossary.GlossaryId] = body.Entries
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(glossary)
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/entries") && self.glossaries[id] != nil:
+		w.Write([]byte(self.entries[id]))
+	case r.Method == http.MethodDelete && self.glossaries[id] != nil:
+		delete(self.glossaries, id)
+		self.deleted = append(self.deleted, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		self.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestDeepl_SyncGlossary(t *testing.T) {
+	server := &glossaryServer{t: t, glossaries: map[string]*GlossaryResult{}, entries: map[string]string{}}
+	deepl := newTestDeepl(t, server.ServeHTTP)
+	ctx := context.Background()
+	entries := GlossaryEntries{{Source: "Save", Target: "Speichern"}, {Source: "Open", Target: "Öffnen"}}
+
+	result, err := deepl.SyncGlossary(ctx, "ui", "EN", "DE", entries, SyncGlossaryOptions{}).Sync()
+	if err != nil || !result.Created || result.PreviousGlossaryId != "" || len(result.Diff.Added) != 2 || server.created != 1 {
+		t.Fatalf("result %+v, err = %v", result, err)
+	}
+	first := result.GlossaryId
+
+	result, err = deepl.SyncGlossary(ctx, "ui", "en", "de", entries, SyncGlossaryOptions{}).Sync()
+	if err != nil || result.Created || result.GlossaryId != first || !result.Diff.Empty() || server.created != 1 {
+		t.Fatalf("an unchanged glossary was replaced, result %+v, err = %v", result, err)
+	}
+
+	changed := GlossaryEntries{{Source: "Save", Target: "Sichern"}, {Source: "Close", Target: "Schließen"}}
+	output := &bytes.Buffer{}
+	result, err = deepl.SyncGlossary(ctx, "ui", "EN", "DE", changed, SyncGlossaryOptions{DryRun: true, Output: output}).Sync()
+	if err != nil || !result.Created || result.GlossaryId != first || server.created != 1 || len(server.deleted) != 0 {
+		t.Fatalf("a dry run changed the glossaries, result %+v, err = %v", result, err)
+	}
+	plan := "create glossary ui (EN -> DE) with 2 entries: 1 added, 1 removed, 1 changed\n" +
+		"+ Close\tSchließen\n- Open\tÖffnen\n~ Save\tSpeichern -> Sichern\ndelete glossary " + first + "\n"
+	if output.String() != plan {
+		t.Fatalf("plan %q, want %q", output, plan)
+	}
+
+	result, err = deepl.SyncGlossary(ctx, "ui", "EN", "DE", changed, SyncGlossaryOptions{}).Sync()
+	if err != nil || !result.Created || result.PreviousGlossaryId != first || result.GlossaryId == first {
+		t.Fatalf("result %+v, err = %v", result, err)
+	}
+	if len(server.deleted) != 1 || server.deleted[0] != first || server.entries[result.GlossaryId] != changed.FormatTSV() {
+		t.Fatalf("deleted %v, entries %q", server.deleted, server.entries[result.GlossaryId])
+	}
+}
@@ -0,0 +1,92 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var errUnboundJob = errors.New("document job is not bound to a client, call Bind first")
+
+// DocumentJob Is a handle of an uploaded document bound to the client that uploaded it.
+// Jobs are marshaled as their document id and key, an unmarshaled job must be bound to a
+// client with Bind before its methods can be used.
+type DocumentJob struct {
+	DocumentResult
+	client *Deepl
+}
+
+// NewDocumentJob Binds an uploaded document to the client
+func (self *Deepl) NewDocumentJob(document DocumentResult) *DocumentJob {
+	return &DocumentJob{
+		DocumentResult: document,
+		client:         self,
+	}
+}
+
+// DocumentTranslateJob Is DocumentTranslateWithParams returning a job handle of the uploaded document
+func (self *Deepl) DocumentTranslateJob(ctx context.Context, document io.Reader, filename string, body *DocumentTranslateParams) *CMD[*DocumentJob] {
+	return NewCMD(ctx, func() (*DocumentJob, error) {
+		result, err := self.doDocumentTranslate(ctx, document, filename, body)
+		if err != nil {
+			return nil, err
+		}
+		return self.NewDocumentJob(result), nil
+	})
+}
+
+// Bind Binds the job to the client, e.g. after it has been unmarshaled
+func (self *DocumentJob) Bind(client *Deepl) *DocumentJob {
+	self.client = client
+	return self
+}
+
+// Status Is check the current status of the document translation
+func (self *DocumentJob) Status(ctx context.Context) *CMD[CheckDocumentResult] {
+	return NewCMD(ctx, func() (CheckDocumentResult, error) {
+		if self.client == nil {
+			return CheckDocumentResult{}, errUnboundJob
+		}
+		return self.client.doCheckDocumentStatus(ctx, self.DocumentId, self.DocumentKey)
+	})
+}
+
+// Wait Polls the status until the translation is done or failed, a failed translation returns a *DocumentError
+func (self *DocumentJob) Wait(ctx context.Context) *CMD[CheckDocumentResult] {
+	return NewCMD(ctx, func() (CheckDocumentResult, error) {
+		if self.client == nil {
+			return CheckDocumentResult{}, errUnboundJob
+		}
+		return self.client.waitDocument(ctx, self.DocumentId, self.DocumentKey)
+	})
+}
+
+// Download Copies the translated document into w and returns the number of bytes written
+func (self *DocumentJob) Download(ctx context.Context, w io.Writer) *CMD[int64] {
+	return NewCMD(ctx, func() (int64, error) {
+		if self.client == nil {
+			return 0, errUnboundJob
+		}
+		target := &downloadTarget{writer: w}
+		err := self.client.doDownloadDocument(ctx, self.DocumentId, self.DocumentKey, target)
+		return target.written, err
+	})
+}
+
+func (self DocumentJob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(self.DocumentResult)
+}
+
+// UnmarshalJSON Restores the document id and key and validates their format, the job stays unbound
+func (self *DocumentJob) UnmarshalJSON(data []byte) error {
+	result := DocumentResult{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	if err := validateDocumentIdAndKey(result.DocumentId, result.DocumentKey); err != nil {
+		return err
+	}
+	self.DocumentResult = result
+	return nil
+}
@@ -0,0 +1,57 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDeepl_DocumentTranslateJob(t *testing.T) {
+	server := &documentServer{polls: 2}
+	deepl := newDocumentTestDeepl(t, server)
+	body := &DocumentTranslateParams{}
+	body.TargetLang = "DE"
+	job, err := deepl.DocumentTranslateJob(context.Background(), strings.NewReader("job"), "input.txt", body).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := job.Status(context.Background()).Sync()
+	if err != nil || status.Status != DocumentStatusTranslating {
+		t.Fatalf("Status = %+v, %v", status, err)
+	}
+
+	// persist and rehydrate the job as if the process had restarted
+	data, err := json.Marshal(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"document_id":"`+testDocumentId+`","document_key":"`+testDocumentKey+`"}` {
+		t.Fatalf("marshaled job %s", data)
+	}
+	restored := &DocumentJob{}
+	if err = json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = restored.Wait(context.Background()).Sync(); !errors.Is(err, errUnboundJob) {
+		t.Fatalf("err = %v, want %v", err, errUnboundJob)
+	}
+	restored.Bind(deepl)
+	if status, err = restored.Wait(context.Background()).Sync(); err != nil || status.Status != DocumentStatusDone {
+		t.Fatalf("Wait = %+v, %v", status, err)
+	}
+	output := &bytes.Buffer{}
+	written, err := restored.Download(context.Background(), output).Sync()
+	if err != nil || written != 3 || output.String() != "JOB" {
+		t.Fatalf("Download = %d, %q, %v", written, output, err)
+	}
+}
+
+func TestDocumentJob_UnmarshalJSON(t *testing.T) {
+	job := &DocumentJob{}
+	if err := json.Unmarshal([]byte(`{"document_id":"invalid","document_key":"invalid"}`), job); err == nil {
+		t.Fatal("expected error for an invalid document id")
+	}
+}
@@ -0,0 +1,173 @@
+package deepl
+
+import (
+	"strings"
+)
+
+// Language Is a deepl language code, e.g. DE or EN-GB
+type Language string
+
+const (
+	LanguageArabic               Language = "AR"
+	LanguageBulgarian            Language = "BG"
+	LanguageCzech                Language = "CS"
+	LanguageDanish               Language = "DA"
+	LanguageGerman               Language = "DE"
+	LanguageGreek                Language = "EL"
+	LanguageEnglish              Language = "EN" // source only, use LanguageEnglishBritish or LanguageEnglishAmerican as target
+	LanguageEnglishBritish       Language = "EN-GB"
+	LanguageEnglishAmerican      Language = "EN-US"
+	LanguageSpanish              Language = "ES"
+	LanguageSpanishLatinAmerican Language = "ES-419"
+	LanguageEstonian             Language = "ET"
+	LanguageFinnish              Language = "FI"
+	LanguageFrench               Language = "FR"
+	LanguageHebrew               Language = "HE"
+	LanguageHungarian            Language = "HU"
+	LanguageIndonesian           Language = "ID"
+	LanguageItalian              Language = "IT"
+	LanguageJapanese             Language = "JA"
+	LanguageKorean               Language = "KO"
+	LanguageLithuanian           Language = "LT"
+	LanguageLatvian              Language = "LV"
+	LanguageNorwegian            Language = "NB"
+	LanguageDutch                Language = "NL"
+	LanguagePolish               Language = "PL"
+	LanguagePortuguese           Language = "PT" // source only, use LanguagePortugueseBrazilian or LanguagePortugueseEuropean as target
+	LanguagePortugueseBrazilian  Language = "PT-BR"
+	LanguagePortugueseEuropean   Language = "PT-PT"
+	LanguageRomanian             Language = "RO"
+	LanguageRussian              Language = "RU"
+	LanguageSlovak               Language = "SK"
+	LanguageSlovenian            Language = "SL"
+	LanguageSwedish              Language = "SV"
+	LanguageThai                 Language = "TH"
+	LanguageTurkish              Language = "TR"
+	LanguageUkrainian            Language = "UK"
+	LanguageVietnamese           Language = "VI"
+	LanguageChinese              Language = "ZH"
+	LanguageChineseSimplified    Language = "ZH-HANS"
+	LanguageChineseTraditional   Language = "ZH-HANT"
+)
+
+// languageVariants Are the regional variants of the languages that have them,
+// EN and PT are only accepted as target languages in one of their variants
+var languageVariants = map[Language][]Language{
+	LanguageEnglish:    {LanguageEnglishBritish, LanguageEnglishAmerican},
+	LanguageSpanish:    {LanguageSpanishLatinAmerican},
+	LanguagePortuguese: {LanguagePortugueseBrazilian, LanguagePortugueseEuropean},
+	LanguageChinese:    {LanguageChineseSimplified, LanguageChineseTraditional},
+}
+
+// languageAliases Maps common language tags that deepl does not accept to the deepl language code
+var languageAliases = map[string]Language{
+	"EN-UK": LanguageEnglishBritish,
+	"NO":    LanguageNorwegian,
+	"ZH-CN": LanguageChineseSimplified,
+	"ZH-SG": LanguageChineseSimplified,
+	"ZH-TW": LanguageChineseTraditional,
+	"ZH-HK": LanguageChineseTraditional,
+	"ZH-MO": LanguageChineseTraditional,
+}
+
+var (
+	sourceLanguages = map[Language]bool{}
+	targetLanguages = map[Language]bool{}
+)
+
+func init() {
+	for _, language := range []Language{
+		LanguageArabic, LanguageBulgarian, LanguageCzech, LanguageDanish, LanguageGerman, LanguageGreek,
+		LanguageEnglish, LanguageSpanish, LanguageEstonian, LanguageFinnish, LanguageFrench, LanguageHebrew,
+		LanguageHungarian, LanguageIndonesian, LanguageItalian, LanguageJapanese, LanguageKorean,
+		LanguageLithuanian, LanguageLatvian, LanguageNorwegian, LanguageDutch, LanguagePolish,
+		LanguagePortuguese, LanguageRomanian, LanguageRussian, LanguageSlovak, LanguageSlovenian,
+		LanguageSwedish, LanguageThai, LanguageTurkish, LanguageUkrainian, LanguageVietnamese, LanguageChinese,
+	} {
+		sourceLanguages[language] = true
+		targetLanguages[language] = true
+	}
+	for _, variants := range languageVariants {
+		for _, variant := range variants {
+			targetLanguages[variant] = true
+		}
+	}
+	delete(targetLanguages, LanguageEnglish)
+	delete(targetLanguages, LanguagePortuguese)
+}
+
+// NormalizeLanguage Converts a language tag like en_us, zh-CN or de-AT into a deepl language code.
+// Regions are only kept for the regional variants deepl supports, other regions are dropped.
+func NormalizeLanguage(code string) Language {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	if language, ok := languageAliases[code]; ok {
+		return language
+	}
+	language := Language(code)
+	if sourceLanguages[language] || targetLanguages[language] {
+		return language
+	}
+	return language.Base()
+}
+
+// Base Returns the language without its region, e.g. EN for EN-GB
+func (self Language) Base() Language {
+	if index := strings.IndexByte(string(self), '-'); index >= 0 {
+		return self[:index]
+	}
+	return self
+}
+
+// Variants Returns the regional variants of the language, nil if it has none
+func (self Language) Variants() []Language {
+	return languageVariants[self.Base()]
+}
+
+// ValidSource Reports whether deepl accepts the language as source language, regional variants are not accepted
+func (self Language) ValidSource() bool {
+	return sourceLanguages[self]
+}
+
+// ValidTarget Reports whether deepl accepts the language as target language
+func (self Language) ValidTarget() bool {
+	return targetLanguages[self]
+}
+
+func (self Language) String() string {
+	return string(self)
+}
+
+// validateLanguages Normalizes the source and target language and checks that deepl accepts them,
+// empty languages are returned unchanged
+func (self *Deepl) validateLanguages(source, target string) (string, string, error) {
+	if self.config.DisableLanguageValidation {
+		return source, target, nil
+	}
+	if source != "" {
+		language := NormalizeLanguage(source)
+		if !language.ValidSource() {
+			message := "unsupported source language"
+			if base := language.Base(); base != language && base.ValidSource() {
+				message = "regional variants are not supported as source language, use " + string(base)
+			}
+			return "", "", &ValidationError{Field: "source_lang", Value: source, Message: message}
+		}
+		source = string(language)
+	}
+	if target != "" {
+		language := NormalizeLanguage(target)
+		if !language.ValidTarget() {
+			message := "unsupported target language"
+			if variants := language.Variants(); len(variants) > 0 {
+				names := make([]string, len(variants))
+				for i, variant := range variants {
+					names[i] = string(variant)
+				}
+				message = "use one of the regional variants " + strings.Join(names, ", ")
+			}
+			return "", "", &ValidationError{Field: "target_lang", Value: target, Message: message}
+		}
+		target = string(language)
+	}
+	return source, target, nil
+}
@@ -0,0 +1,95 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := map[string]Language{
+		"de":      LanguageGerman,
+		" en_us ": LanguageEnglishAmerican,
+		"en-uk":   LanguageEnglishBritish,
+		"pt-br":   LanguagePortugueseBrazilian,
+		"zh-cn":   LanguageChineseSimplified,
+		"zh_TW":   LanguageChineseTraditional,
+		"zh-Hant": LanguageChineseTraditional,
+		"es-419":  LanguageSpanishLatinAmerican,
+		"de-AT":   LanguageGerman,
+		"en-AU":   LanguageEnglish,
+		"no":      LanguageNorwegian,
+	}
+	for code, want := range tests {
+		if language := NormalizeLanguage(code); language != want {
+			t.Errorf("NormalizeLanguage(%q) = %s, want %s", code, language, want)
+		}
+	}
+}
+
+func TestLanguage_Valid(t *testing.T) {
+	tests := []struct {
+		language       Language
+		source, target bool
+	}{
+		{LanguageGerman, true, true},
+		{LanguageEnglish, true, false},
+		{LanguageEnglishBritish, false, true},
+		{LanguagePortuguese, true, false},
+		{LanguagePortugueseEuropean, false, true},
+		{LanguageChinese, true, true},
+		{LanguageChineseTraditional, false, true},
+		{LanguageSpanishLatinAmerican, false, true},
+		{"XX", false, false},
+	}
+	for _, tt := range tests {
+		if tt.language.ValidSource() != tt.source || tt.language.ValidTarget() != tt.target {
+			t.Errorf("%s: source %v, target %v, want %v, %v", tt.language, tt.language.ValidSource(), tt.language.ValidTarget(), tt.source, tt.target)
+		}
+	}
+	if variants := LanguageEnglishAmerican.Variants(); len(variants) != 2 || variants[0] != LanguageEnglishBritish {
+		t.Errorf("variants of EN-US = %v", variants)
+	}
+}
+
+func TestDeepl_TextTranslateLanguageValidation(t *testing.T) {
+	var sent TextTranslateParams
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(TextTranslateResultOptional{Translations: []*TextResult{{Text: "hallo"}}})
+	})
+	if _, err := deepl.TextTranslateWithSource("hello", "en", "de").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if sent.SourceLang != "EN" || sent.TargetLang != "DE" {
+		t.Fatalf("sent languages %s -> %s, want EN -> DE", sent.SourceLang, sent.TargetLang)
+	}
+
+	tests := []struct {
+		source, target, field string
+	}{
+		{"", "EN", "target_lang"},
+		{"", "XX", "target_lang"},
+		{"EN-GB", "DE", "source_lang"},
+	}
+	for _, tt := range tests {
+		sent = TextTranslateParams{}
+		_, err := deepl.TextTranslateWithSource("hello", tt.source, tt.target).Sync()
+		validationErr := &ValidationError{}
+		if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+			t.Errorf("%s -> %s: err = %v, want a %s ValidationError", tt.source, tt.target, err, tt.field)
+		}
+		if sent.TargetLang != "" {
+			t.Errorf("%s -> %s: an invalid request was sent", tt.source, tt.target)
+		}
+	}
+
+	deepl.config.DisableLanguageValidation = true
+	body := &TextTranslateParams{Text: []string{"hello"}}
+	body.TargetLang = "xx"
+	if _, err := deepl.TextTranslateWithParams(context.Background(), body).Sync(); err != nil || sent.TargetLang != "xx" {
+		t.Fatalf("err = %v, sent target %s", err, sent.TargetLang)
+	}
+}
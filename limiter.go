@@ -0,0 +1,70 @@
+package deepl
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// tokenBucket Is a client-side rate limiter shared by all goroutines using the same client.
+// A nil tokenBucket never blocks.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // tokens refilled per second
+	capacity float64 // maximum number of tokens that can be accumulated
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate, capacity float64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{
+		rate:     rate,
+		capacity: capacity,
+		tokens:   capacity,
+		last:     time.Now(),
+	}
+}
+
+// wait Takes n tokens from the bucket and blocks until they are available or the context is done.
+// Requests larger than the capacity are allowed, they just wait for the corresponding refill time.
+func (self *tokenBucket) wait(ctx context.Context, n float64) error {
+	if self == nil || n <= 0 {
+		return nil
+	}
+	self.mu.Lock()
+	now := time.Now()
+	self.tokens += now.Sub(self.last).Seconds() * self.rate
+	if self.tokens > self.capacity {
+		self.tokens = self.capacity
+	}
+	self.last = now
+	self.tokens -= n
+	var delay time.Duration
+	if self.tokens < 0 {
+		delay = time.Duration(-self.tokens / self.rate * float64(time.Second))
+	}
+	self.mu.Unlock()
+	if err := sleepWithContext(ctx, delay); err != nil {
+		self.mu.Lock()
+		self.tokens += n
+		self.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// countCharacters Returns the number of characters deepl bills for the texts
+func countCharacters(texts []string) int {
+	count := 0
+	for _, text := range texts {
+		count += utf8.RuneCountInString(text)
+	}
+	return count
+}
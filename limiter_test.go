@@ -0,0 +1,57 @@
+package deepl
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_wait(t *testing.T) {
+	bucket := newTokenBucket(100, 1)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := bucket.wait(context.Background(), 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("6 tokens at 100/s with burst 1 took %s", elapsed)
+	}
+}
+
+func TestTokenBucket_waitContext(t *testing.T) {
+	bucket := newTokenBucket(1, 1)
+	if err := bucket.wait(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := bucket.wait(ctx, 1); err != context.DeadlineExceeded {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	var nilBucket *tokenBucket
+	if err := nilBucket.wait(ctx, 100); err != nil {
+		t.Fatalf("nil bucket must not block, err = %v", err)
+	}
+}
+
+func TestDeepl_CharactersPerMinute(t *testing.T) {
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"translations":[{"text":"hallo"}]}`))
+	}, func(config *Config) {
+		config.CharactersPerMinute = 600
+	})
+	if _, err := deepl.TextTranslate("0123456789", "DE").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	long := make([]byte, 600)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if _, err := deepl.TextTranslateWithContext(ctx, string(long), "", "DE").Sync(); err != context.DeadlineExceeded {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,240 @@
+package deepl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wnnce/deepl-go/tmx"
+)
+
+// MemoryEntry Is a translation recorded in a TranslationMemory
+type MemoryEntry struct {
+	Key                    CacheKey  `json:"key"`
+	Translation            string    `json:"translation"`
+	DetectedSourceLanguage string    `json:"detected_source_language,omitempty"`
+	CreatedAt              time.Time `json:"created_at"`
+}
+
+// TranslationMemory Is a persistent store of translations that is consulted before a text is
+// sent to the api. Implementations must be safe for concurrent use.
+type TranslationMemory interface {
+	Lookup(key CacheKey) (*MemoryEntry, bool)
+	Record(entry MemoryEntry) error
+}
+
+// FileMemory Is a TranslationMemory backed by an append-only file of json lines. The whole
+// file is loaded on open, when a key is recorded several times the last entry wins.
+// The file can be shared across processes as long as they do not write concurrently.
+type FileMemory struct {
+	mu      sync.RWMutex
+	file    *os.File
+	entries map[CacheKey]*MemoryEntry
+}
+
+// OpenFileMemory Opens or creates the translation memory file at path
+func OpenFileMemory(path string) (*FileMemory, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	memory := &FileMemory{
+		file:    file,
+		entries: make(map[CacheKey]*MemoryEntry),
+	}
+	if err = memory.load(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("load translation memory %s: %w", path, err)
+	}
+	return memory, nil
+}
+
+// load Reads all entries of the file. A truncated last line left by an interrupted
+// write is ignored and overwritten by the next record, a complete last line that only
+// misses its line break, e.g. after editing the file by hand, is kept.
+func (self *FileMemory) load() error {
+	reader := bufio.NewReader(self.file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bytes.TrimSpace(data)) == 0 {
+				return nil
+			}
+			entry := &MemoryEntry{}
+			if json.Unmarshal(data, entry) != nil {
+				return self.file.Truncate(self.size() - int64(len(data)))
+			}
+			self.entries[entry.Key] = entry
+			_, err = self.file.Write([]byte{'\n'})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		entry := &MemoryEntry{}
+		if err = json.Unmarshal(data, entry); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		self.entries[entry.Key] = entry
+	}
+}
+
+func (self *FileMemory) size() int64 {
+	info, err := self.file.Stat()
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+func (self *FileMemory) Lookup(key CacheKey) (*MemoryEntry, bool) {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	entry, ok := self.entries[key]
+	if !ok {
+		return nil, false
+	}
+	value := *entry
+	return &value, true
+}
+
+// Record Appends the entry to the file, a zero CreatedAt is set to the current time
+func (self *FileMemory) Record(entry MemoryEntry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return err
+	}
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if _, err = self.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	self.entries[entry.Key] = &entry
+	return nil
+}
+
+// Entries Returns the current entry of every key ordered by creation time
+func (self *FileMemory) Entries() []MemoryEntry {
+	self.mu.RLock()
+	result := make([]MemoryEntry, 0, len(self.entries))
+	for _, entry := range self.entries {
+		result = append(result, *entry)
+	}
+	self.mu.RUnlock()
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].Key.Text < result[j].Key.Text
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// Len Returns the number of recorded keys
+func (self *FileMemory) Len() int {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	return len(self.entries)
+}
+
+func (self *FileMemory) Close() error {
+	return self.file.Close()
+}
+
+// ExportTMX Writes all entries as a TMX 1.4 document. The source language of entries
+// translated with automatic source detection is the detected language.
+func (self *FileMemory) ExportTMX(w io.Writer) error {
+	document := tmx.New(tmx.AllLanguages)
+	for _, entry := range self.Entries() {
+		source := entry.Key.SourceLang
+		if source == "" {
+			source = entry.DetectedSourceLanguage
+		}
+		document.Add(source, entry.Key.Text, entry.Key.TargetLang, entry.Translation, entry.CreatedAt)
+	}
+	return document.Write(w)
+}
+
+// TMXImportOptions Controls how translation units are turned into stored translations
+type TMXImportOptions struct {
+	// TargetLangs are the deepl target languages to import, all languages of a unit
+	// except its source language are imported when empty
+	TargetLangs []string
+	// Params are applied to the key of every imported translation, e.g. Formality or GlossaryId,
+	// so that the translations are found by requests with the same params
+	Params CacheKey
+}
+
+// SeedTMX Stores the translations of a TMX document in config.Cache and config.Memory, so that
+// texts already translated by humans are not sent to the api. Every translation is stored
+// both with its source language and without one, so that requests relying on source
+// language detection find it as well. Returns the number of imported translations.
+func (self *Deepl) SeedTMX(document *tmx.Document, options TMXImportOptions) (int, error) {
+	if self.config.Cache == nil && self.config.Memory == nil {
+		return 0, errors.New("neither a cache nor a translation memory is configured")
+	}
+	count := 0
+	for _, unit := range document.Units {
+		sourceLang := document.SourceLang(unit)
+		source, ok := unit.Segment(sourceLang)
+		if !ok || strings.TrimSpace(source) == "" {
+			continue
+		}
+		targets := options.TargetLangs
+		if len(targets) == 0 {
+			targets = make([]string, 0, len(unit.Variants))
+			for _, variant := range unit.Variants {
+				if NormalizeLanguage(variant.Lang) != NormalizeLanguage(sourceLang) {
+					targets = append(targets, string(NormalizeLanguage(variant.Lang)))
+				}
+			}
+		}
+		for _, targetLang := range targets {
+			target, ok := targetSegment(unit, targetLang)
+			if !ok {
+				continue
+			}
+			key := options.Params
+			key.Text = source
+			key.TargetLang = string(NormalizeLanguage(targetLang))
+			value := &TextResult{
+				DetectedSourceLanguage: string(NormalizeLanguage(sourceLang).Base()),
+				Text:                   target,
+			}
+			for _, keySource := range []string{value.DetectedSourceLanguage, ""} {
+				key.SourceLang = keySource
+				if err := self.store(key, value); err != nil {
+					return count, err
+				}
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// targetSegment Returns the segment of the unit in the target language. Unlike tmx.Unit.Segment regional
+// variants must match, otherwise e.g. the pt-BR segment would be stored as the translation to PT-PT.
+func targetSegment(unit tmx.Unit, targetLang string) (string, bool) {
+	target := NormalizeLanguage(targetLang)
+	for _, variant := range unit.Variants {
+		if NormalizeLanguage(variant.Lang) == target {
+			return variant.Segment, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,281 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wnnce/deepl-go/tmx"
+)
+
+func TestFileMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+	memory, err := OpenFileMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := CacheKey{Text: "hello", SourceLang: "EN", TargetLang: "DE"}
+	if err = memory.Record(MemoryEntry{Key: key, Translation: "hallo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = memory.Record(MemoryEntry{Key: key, Translation: "Hallo"}); err != nil {
+		t.Fatal(err)
+	}
+	memory.Close()
+
+	// simulate a write interrupted by a crash
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	file.WriteString(`{"key":{"text":"trunc`)
+	file.Close()
+
+	memory, err = OpenFileMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	entry, ok := memory.Lookup(key)
+	if !ok || entry.Translation != "Hallo" || entry.CreatedAt.IsZero() {
+		t.Fatalf("Lookup = %+v, %v", entry, ok)
+	}
+	if err = memory.Record(MemoryEntry{Key: CacheKey{Text: "world", TargetLang: "DE"}, Translation: "Welt"}); err != nil {
+		t.Fatal(err)
+	}
+	if memory.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", memory.Len())
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 3 || strings.Contains(string(data), "trunc") {
+		t.Fatalf("unexpected file content:\n%s", data)
+	}
+}
+
+func TestFileMemory_TagParams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+	memory, err := OpenFileMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := &TextTranslateParams{TagHandling: "xml", IgnoreTags: []string{"x", "code"}, SplitSentences: "0"}
+	body.TargetLang = "DE"
+	key := newCacheKey(body, "<p>hello <x>world</x></p>")
+	if err = memory.Record(MemoryEntry{Key: key, Translation: "<p>hallo <x>world</x></p>"}); err != nil {
+		t.Fatal(err)
+	}
+	memory.Close()
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"ignore_tags":"code,x"`) || !strings.Contains(string(data), `"split_sentences":"0"`) {
+		t.Fatalf("unexpected file content:\n%s", data)
+	}
+	if memory, err = OpenFileMemory(path); err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	if _, ok := memory.Lookup(key); !ok {
+		t.Fatal("the recorded key was not found after reopening")
+	}
+	body.IgnoreTags = nil
+	if _, ok := memory.Lookup(newCacheKey(body, "<p>hello <x>world</x></p>")); ok {
+		t.Fatal("a key without ignore tags found the translation of other params")
+	}
+}
+
+func TestFileMemory_LastLineWithoutBreak(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+	os.WriteFile(path, []byte(`{"key":{"text":"hello","target_lang":"DE"},"translation":"Hallo"}`), 0o644)
+	memory, err := OpenFileMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	if entry, ok := memory.Lookup(CacheKey{Text: "hello", TargetLang: "DE"}); !ok || entry.Translation != "Hallo" {
+		t.Fatalf("the complete last line was dropped, Lookup = %+v, %v", entry, ok)
+	}
+	if err = memory.Record(MemoryEntry{Key: CacheKey{Text: "world", TargetLang: "DE"}, Translation: "Welt"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "Welt") {
+		t.Fatalf("unexpected file content:\n%s", data)
+	}
+}
+
+func TestFileMemory_ExportTMX(t *testing.T) {
+	memory, err := OpenFileMemory(filepath.Join(t.TempDir(), "memory.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	memory.Record(MemoryEntry{Key: CacheKey{Text: "a < b", TargetLang: "DE"}, Translation: "a < b", DetectedSourceLanguage: "EN", CreatedAt: created})
+	buffer := &bytes.Buffer{}
+	if err = memory.ExportTMX(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<tmx version="1.4">`,
+		`<tu srclang="EN" creationdate="20240501T123000Z">`,
+		`<tuv xml:lang="EN">`,
+		`<seg>a &lt; b</seg>`,
+		`<tuv xml:lang="DE">`,
+	} {
+		if !strings.Contains(buffer.String(), want) {
+			t.Fatalf("exported tmx does not contain %s:\n%s", want, buffer)
+		}
+	}
+}
+
+func TestDeepl_TextTranslateMemory(t *testing.T) {
+	var requests int32
+	path := filepath.Join(t.TempDir(), "memory.jsonl")
+	memory, err := OpenFileMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Memory = memory
+	})
+	if _, err = deepl.TextsTranslate([]string{"hello", "world"}, "DE").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	memory.Close()
+
+	memory, err = OpenFileMemory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	deepl = newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Memory = memory
+	})
+	params := AcquireTextTranslateParams()
+	defer RecycleParams(params)
+	params.Text = []string{"world", "hello"}
+	params.TargetLang = "DE"
+	results, err := deepl.TextTranslateWithParams(context.Background(), params).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 || results[0].Text != "WORLD" || results[1].Text != "HELLO" || results[0].DetectedSourceLanguage != "EN" {
+		t.Fatalf("unexpected results %v %v after %d requests", results[0], results[1], requests)
+	}
+}
+
+// failingMemory Is a TranslationMemory whose disk is full
+type failingMemory struct{}
+
+func (failingMemory) Lookup(key CacheKey) (*MemoryEntry, bool) {
+	return nil, false
+}
+
+func (failingMemory) Record(entry MemoryEntry) error {
+	return errors.New("disk full")
+}
+
+func TestDeepl_TextTranslateMemoryError(t *testing.T) {
+	var requests int32
+	var memoryErrs []error
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Memory = failingMemory{}
+		config.OnMemoryError = func(err error) {
+			memoryErrs = append(memoryErrs, err)
+		}
+	})
+	result, err := deepl.TextTranslate("hello", "DE").Sync()
+	if err != nil || result == nil || result.Text != "HELLO" || requests != 1 {
+		t.Fatalf("result %v, err = %v after %d requests", result, err, requests)
+	}
+	if len(memoryErrs) != 1 || !strings.Contains(memoryErrs[0].Error(), "disk full") {
+		t.Fatalf("memory errors %v", memoryErrs)
+	}
+}
+
+func TestDeepl_SeedTMX(t *testing.T) {
+	document, err := tmx.Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="vendor" creationtoolversion="1" segtype="sentence" o-tmf="vendor" adminlang="en" srclang="en-US" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="en-US"><seg>Save</seg></tuv>
+      <tuv xml:lang="de-DE"><seg>Sichern</seg></tuv>
+      <tuv xml:lang="pt-BR"><seg>Salvar</seg></tuv>
+    </tu>
+  </body>
+</tmx>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests int32
+	deepl := newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Cache = NewLRUCache(100, 0)
+	})
+	count, err := deepl.SeedTMX(document, TMXImportOptions{})
+	if err != nil || count != 2 {
+		t.Fatalf("SeedTMX = %d, %v", count, err)
+	}
+	results, err := deepl.TextsTranslate([]string{"Save", "Open"}, "DE").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Text != "Sichern" || results[1].Text != "OPEN" || requests != 1 {
+		t.Fatalf("unexpected results %v %v after %d requests", results[0], results[1], requests)
+	}
+	result, err := deepl.TextTranslateWithSource("Save", "EN", "PT-BR").Sync()
+	if err != nil || result.Text != "Salvar" || requests != 1 {
+		t.Fatalf("unexpected result %v, %v after %d requests", result, err, requests)
+	}
+
+	memory, err := OpenFileMemory(filepath.Join(t.TempDir(), "memory.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	deepl = newTestDeepl(t, echoTranslateHandler(t, &requests), func(config *Config) {
+		config.Memory = memory
+	})
+	if count, err = deepl.SeedTMX(document, TMXImportOptions{TargetLangs: []string{"DE"}, Params: CacheKey{Formality: FormalityMore}}); err != nil || count != 1 {
+		t.Fatalf("SeedTMX = %d, %v", count, err)
+	}
+	if entry, ok := memory.Lookup(CacheKey{Text: "Save", SourceLang: "EN", TargetLang: "DE", Formality: FormalityMore}); !ok || entry.Translation != "Sichern" {
+		t.Fatalf("Lookup = %v, %v", entry, ok)
+	}
+}
+
+func TestDeepl_SeedTMXRegionalTargets(t *testing.T) {
+	document, err := tmx.Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="vendor" creationtoolversion="1" segtype="sentence" o-tmf="vendor" adminlang="en" srclang="en" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="en"><seg>Color</seg></tuv>
+      <tuv xml:lang="en-GB"><seg>Colour</seg></tuv>
+      <tuv xml:lang="pt-BR"><seg>Cor</seg></tuv>
+      <tuv xml:lang="zh-Hans"><seg>颜色</seg></tuv>
+    </tu>
+  </body>
+</tmx>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewLRUCache(100, 0)
+	deepl := newTestDeepl(t, echoTranslateHandler(t, new(int32)), func(config *Config) {
+		config.Cache = cache
+	})
+	count, err := deepl.SeedTMX(document, TMXImportOptions{TargetLangs: []string{"PT-PT", "PT-BR", "EN-GB", "EN-US", "ZH-HANS", "ZH-HANT"}})
+	if err != nil || count != 3 {
+		t.Fatalf("SeedTMX = %d, %v", count, err)
+	}
+	for target, want := range map[string]string{"PT-BR": "Cor", "EN-GB": "Colour", "ZH-HANS": "颜色", "PT-PT": "", "EN-US": "", "ZH-HANT": ""} {
+		result, ok := cache.Get(CacheKey{Text: "Color", SourceLang: "EN", TargetLang: target})
+		if want == "" && ok {
+			t.Errorf("%s: stored %q of another regional variant", target, result.Text)
+		}
+		if want != "" && (!ok || result.Text != want) {
+			t.Errorf("%s: got %v, %v, want %s", target, result, ok, want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package deepl
+
+import "net/http"
+
+// RequestHandler Sends a request and returns its response
+type RequestHandler func(req *http.Request) (*http.Response, error)
+
+// Middleware Wraps every request sent by the client, including every retry attempt.
+// Implementations can modify the request, inspect the response or return their own
+// response or error without calling next.
+type Middleware interface {
+	Handle(req *http.Request, next RequestHandler) (*http.Response, error)
+}
+
+// MiddlewareFunc Is an adapter to use ordinary functions as Middleware
+type MiddlewareFunc func(req *http.Request, next RequestHandler) (*http.Response, error)
+
+func (fn MiddlewareFunc) Handle(req *http.Request, next RequestHandler) (*http.Response, error) {
+	return fn(req, next)
+}
+
+// chainMiddlewares Wraps the handler so that the first middleware is executed first
+func chainMiddlewares(handler RequestHandler, middlewares []Middleware) RequestHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		middleware, next := middlewares[i], handler
+		handler = func(req *http.Request) (*http.Response, error) {
+			return middleware.Handle(req, next)
+		}
+	}
+	return handler
+}
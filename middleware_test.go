@@ -0,0 +1,71 @@
+package deepl
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDeepl_Middlewares(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return MiddlewareFunc(func(req *http.Request, next RequestHandler) (*http.Response, error) {
+			order = append(order, name+":request")
+			req.Header.Add("X-Middleware", name)
+			resp, err := next(req)
+			order = append(order, name+":response")
+			return resp, err
+		})
+	}
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := strings.Join(r.Header.Values("X-Middleware"), ","); got != "first,second" {
+			t.Errorf("X-Middleware = %s, want first,second", got)
+		}
+		w.Write([]byte(`[{"language":"DE","name":"German"}]`))
+	}, func(config *Config) {
+		config.Middlewares = []Middleware{record("first"), record("second")}
+	})
+	if _, err := deepl.Languages().Sync(); err != nil {
+		t.Fatal(err)
+	}
+	want := "first:request,second:request,second:response,first:response"
+	if got := strings.Join(order, ","); got != want {
+		t.Fatalf("order = %s, want %s", got, want)
+	}
+}
+
+func TestDeepl_MiddlewareFaultInjection(t *testing.T) {
+	injected := errors.New("injected")
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request must not reach the server")
+	}, func(config *Config) {
+		config.Middlewares = []Middleware{MiddlewareFunc(func(req *http.Request, next RequestHandler) (*http.Response, error) {
+			return nil, injected
+		})}
+	})
+	if _, err := deepl.Usage().Sync(); !errors.Is(err, injected) {
+		t.Fatalf("err = %v, want %v", err, injected)
+	}
+
+	var calls int
+	deepl = newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"character_count":1}`))
+	}, func(config *Config) {
+		config.Retry = testRetryPolicy
+		config.Middlewares = []Middleware{MiddlewareFunc(func(req *http.Request, next RequestHandler) (*http.Response, error) {
+			if calls++; calls == 1 {
+				return &http.Response{StatusCode: 529, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+			}
+			return next(req)
+		})}
+	})
+	result, err := deepl.Usage().Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CharacterCount != 1 || calls != 2 {
+		t.Fatalf("unexpected result %+v after %d calls", result, calls)
+	}
+}
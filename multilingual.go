@@ -0,0 +1,197 @@
+package deepl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NewDictionary Returns the dictionary of the language pair with the entries in the tsv format
+func NewDictionary(source, target string, entries GlossaryEntries) DictionaryParams {
+	return DictionaryParams{
+		SourceLang:    source,
+		TargetLang:    target,
+		Entries:       entries.FormatTSV(),
+		EntriesFormat: EntriesFormatTSV,
+	}
+}
+
+// CreateMultilingualGlossary Is create a v3 glossary with a dictionary for every language pair
+func (self *Deepl) CreateMultilingualGlossary(body *CreateMultilingualGlossaryParams) *CMD[*MultilingualGlossaryResult] {
+	return self.CreateMultilingualGlossaryWithContext(context.Background(), body)
+}
+
+func (self *Deepl) CreateMultilingualGlossaryWithContext(ctx context.Context, body *CreateMultilingualGlossaryParams) *CMD[*MultilingualGlossaryResult] {
+	return NewCMD(ctx, func() (*MultilingualGlossaryResult, error) {
+		request, err := self.createRequestWithJSON(ctx, multilingualGlossariesUri, http.MethodPost, body)
+		if err != nil {
+			return nil, err
+		}
+		result := &MultilingualGlossaryResult{}
+		err = self.doRequest(request, result)
+		return result, err
+	})
+}
+
+// ListMultilingualGlossaries Is list all v3 glossaries
+func (self *Deepl) ListMultilingualGlossaries() *CMD[[]*MultilingualGlossaryResult] {
+	return self.ListMultilingualGlossariesWithContext(context.Background())
+}
+
+func (self *Deepl) ListMultilingualGlossariesWithContext(ctx context.Context) *CMD[[]*MultilingualGlossaryResult] {
+	return NewCMD(ctx, func() ([]*MultilingualGlossaryResult, error) {
+		request, err := self.createRequestWithJSON(ctx, multilingualGlossariesUri, http.MethodGet, nil)
+		if err != nil {
+			return nil, err
+		}
+		result := &MultilingualGlossariesOptional{}
+		if err = self.doRequest(request, result); err != nil {
+			return nil, err
+		}
+		return result.Glossaries, nil
+	})
+}
+
+// MultilingualGlossaryDetail Is retrieve the details of a v3 glossary
+func (self *Deepl) MultilingualGlossaryDetail(glossaryId string) *CMD[*MultilingualGlossaryResult] {
+	return self.MultilingualGlossaryDetailWithContext(context.Background(), glossaryId)
+}
+
+func (self *Deepl) MultilingualGlossaryDetailWithContext(ctx context.Context, glossaryId string) *CMD[*MultilingualGlossaryResult] {
+	return NewCMD(ctx, func() (*MultilingualGlossaryResult, error) {
+		return self.doMultilingualGlossary(ctx, glossaryId, http.MethodGet, nil)
+	})
+}
+
+// UpdateMultilingualGlossary Renames a v3 glossary and merges entries into its dictionaries,
+// entries of existing source terms are replaced and dictionaries of new language pairs are added
+func (self *Deepl) UpdateMultilingualGlossary(glossaryId string, body *UpdateMultilingualGlossaryParams) *CMD[*MultilingualGlossaryResult] {
+	return self.UpdateMultilingualGlossaryWithContext(context.Background(), glossaryId, body)
+}
+
+func (self *Deepl) UpdateMultilingualGlossaryWithContext(ctx context.Context, glossaryId string, body *UpdateMultilingualGlossaryParams) *CMD[*MultilingualGlossaryResult] {
+	return NewCMD(ctx, func() (*MultilingualGlossaryResult, error) {
+		return self.doMultilingualGlossary(ctx, glossaryId, http.MethodPatch, body)
+	})
+}
+
+// RenameMultilingualGlossary Is rename a v3 glossary
+func (self *Deepl) RenameMultilingualGlossary(glossaryId, name string) *CMD[*MultilingualGlossaryResult] {
+	return self.UpdateMultilingualGlossaryWithContext(context.Background(), glossaryId, &UpdateMultilingualGlossaryParams{Name: name})
+}
+
+func (self *Deepl) doMultilingualGlossary(ctx context.Context, glossaryId, method string, body any) (*MultilingualGlossaryResult, error) {
+	if err := validateGlossaryId(glossaryId); err != nil {
+		return nil, err
+	}
+	request, err := self.createRequestWithJSON(ctx, fmt.Sprintf(multilingualGlossaryUri, glossaryId), method, body)
+	if err != nil {
+		return nil, err
+	}
+	result := &MultilingualGlossaryResult{}
+	err = self.doRequest(request, result)
+	return result, err
+}
+
+// ReplaceDictionary Replaces all entries of the dictionary of the language pair,
+// or adds the dictionary if the glossary does not contain the language pair
+func (self *Deepl) ReplaceDictionary(glossaryId string, body *DictionaryParams) *CMD[*DictionaryResult] {
+	return self.ReplaceDictionaryWithContext(context.Background(), glossaryId, body)
+}
+
+func (self *Deepl) ReplaceDictionaryWithContext(ctx context.Context, glossaryId string, body *DictionaryParams) *CMD[*DictionaryResult] {
+	return NewCMD(ctx, func() (*DictionaryResult, error) {
+		if err := validateGlossaryId(glossaryId); err != nil {
+			return nil, err
+		}
+		request, err := self.createRequestWithJSON(ctx, fmt.Sprintf(multilingualDictionariesUri, glossaryId), http.MethodPut, body)
+		if err != nil {
+			return nil, err
+		}
+		result := &DictionaryResult{}
+		err = self.doRequest(request, result)
+		return result, err
+	})
+}
+
+// DictionaryEntries Is retrieve the entries of the dictionary of the language pair
+func (self *Deepl) DictionaryEntries(glossaryId, source, target string) *CMD[GlossaryEntries] {
+	return self.DictionaryEntriesWithContext(context.Background(), glossaryId, source, target)
+}
+
+func (self *Deepl) DictionaryEntriesWithContext(ctx context.Context, glossaryId, source, target string) *CMD[GlossaryEntries] {
+	return NewCMD(ctx, func() (GlossaryEntries, error) {
+		if err := validateGlossaryId(glossaryId); err != nil {
+			return nil, err
+		}
+		requestUri := fmt.Sprintf(multilingualGlossaryEntryUri, glossaryId) + "?" + dictionaryQuery(source, target)
+		request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodGet, nil)
+		if err != nil {
+			return nil, err
+		}
+		result := &DictionaryEntriesOptional{}
+		if err = self.doRequest(request, result); err != nil {
+			return nil, err
+		}
+		if len(result.Dictionaries) == 0 {
+			return GlossaryEntries{}, nil
+		}
+		dictionary := result.Dictionaries[0]
+		if dictionary.EntriesFormat == EntriesFormatCSV {
+			return ParseGlossaryCSV(dictionary.Entries)
+		}
+		return ParseGlossaryTSV(dictionary.Entries)
+	})
+}
+
+// DeleteMultilingualGlossary Is delete a v3 glossary with all its dictionaries
+func (self *Deepl) DeleteMultilingualGlossary(glossaryId string) *CMD[struct{}] {
+	return self.DeleteMultilingualGlossaryWithContext(context.Background(), glossaryId)
+}
+
+func (self *Deepl) DeleteMultilingualGlossaryWithContext(ctx context.Context, glossaryId string) *CMD[struct{}] {
+	return NewCMD(ctx, func() (struct{}, error) {
+		if err := validateGlossaryId(glossaryId); err != nil {
+			return struct{}{}, err
+		}
+		request, err := self.createRequestWithJSON(ctx, fmt.Sprintf(multilingualGlossaryUri, glossaryId), http.MethodDelete, nil)
+		if err != nil {
+			return struct{}{}, err
+		}
+		return struct{}{}, self.doRequest(request, nil)
+	})
+}
+
+// DeleteDictionary Is delete the dictionary of the language pair from a v3 glossary
+func (self *Deepl) DeleteDictionary(glossaryId, source, target string) *CMD[struct{}] {
+	return self.DeleteDictionaryWithContext(context.Background(), glossaryId, source, target)
+}
+
+func (self *Deepl) DeleteDictionaryWithContext(ctx context.Context, glossaryId, source, target string) *CMD[struct{}] {
+	return NewCMD(ctx, func() (struct{}, error) {
+		if err := validateGlossaryId(glossaryId); err != nil {
+			return struct{}{}, err
+		}
+		requestUri := fmt.Sprintf(multilingualDictionariesUri, glossaryId) + "?" + dictionaryQuery(source, target)
+		request, err := self.createRequestWithJSON(ctx, requestUri, http.MethodDelete, nil)
+		if err != nil {
+			return struct{}{}, err
+		}
+		return struct{}{}, self.doRequest(request, nil)
+	})
+}
+
+func dictionaryQuery(source, target string) string {
+	query := url.Values{}
+	query.Set("source_lang", source)
+	query.Set("target_lang", target)
+	return query.Encode()
+}
+
+func validateGlossaryId(glossaryId string) error {
+	if !uuidRegex.MatchString(glossaryId) {
+		return fmt.Errorf("GlossaryId does not exist or is not formatted correctly, your glossaryId: %s ", glossaryId)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package deepl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// multilingualServer Simulates the v3 glossary endpoints with a single glossary
+type multilingualServer struct {
+	t            *testing.T
+	name         string
+	dictionaries map[[2]string]string
+	requests     []string
+}
+
+func (self *multilingualServer) glossary() MultilingualGlossaryResult {
+	result := MultilingualGlossaryResult{GlossaryId: testGlossaryId, Name: self.name}
+	for pair, entries := range self.dictionaries {
+		result.Dictionaries = append(result.Dictionaries, DictionaryResult{SourceLang: pair[0], TargetLang: pair[1], EntryCount: int64(strings.Count(entries, "\n"))})
+	}
+	return result
+}
+
+func (self *multilingualServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.requests = append(self.requests, r.Method+" "+r.URL.Path)
+	query := r.URL.Query()
+	pair := [2]string{query.Get("source_lang"), query.Get("target_lang")}
+	switch r.Method + " " + r.URL.Path {
+	case "POST /v3/glossaries":
+		body := CreateMultilingualGlossaryParams{}
+		json.NewDecoder(r.Body).Decode(&body)
+		self.name = body.Name
+		self.dictionaries = map[[2]string]string{}
+		for _, dictionary := range body.Dictionaries {
+			self.dictionaries[[2]string{dictionary.SourceLang, dictionary.TargetLang}] = dictionary.Entries
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(self.glossary())
+	case "GET /v3/glossaries":
+		json.NewEncoder(w).Encode(MultilingualGlossariesOptional{Glossaries: []*MultilingualGlossaryResult{{GlossaryId: testGlossaryId, Name: self.name}}})
+	case "PATCH /v3/glossaries/" + testGlossaryId:
+		body := UpdateMultilingualGlossaryParams{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Name != "" {
+			self.name = body.Name
+		}
+		for _, dictionary := range body.Dictionaries {
+			self.dictionaries[[2]string{dictionary.SourceLang, dictionary.TargetLang}] += dictionary.Entries
+		}
+		json.NewEncoder(w).Encode(self.glossary())
+	case "PUT /v3/glossaries/" + testGlossaryId + "/dictionaries":
+		body := DictionaryParams{}
+		json.NewDecoder(r.Body).Decode(&body)
+		self.dictionaries[[2]string{body.SourceLang, body.TargetLang}] = body.Entries
+		json.NewEncoder(w).Encode(DictionaryResult{SourceLang: body.SourceLang, TargetLang: body.TargetLang, EntryCount: int64(strings.Count(body.Entries, "\n"))})
+	case "GET /v3/glossaries/" + testGlossaryId + "/entries":
+		json.NewEncoder(w).Encode(DictionaryEntriesOptional{Dictionaries: []DictionaryParams{{SourceLang: pair[0], TargetLang: pair[1], Entries: self.dictionaries[pair], EntriesFormat: EntriesFormatTSV}}})
+	case "DELETE /v3/glossaries/" + testGlossaryId + "/dictionaries":
+		delete(self.dictionaries, pair)
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE /v3/glossaries/" + testGlossaryId:
+		self.dictionaries = nil
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		self.t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestDeepl_MultilingualGlossary(t *testing.T) {
+	server := &multilingualServer{t: t}
+	deepl := newTestDeepl(t, server.ServeHTTP)
+	ctx := context.Background()
+	german := GlossaryEntries{{Source: "Save", Target: "Speichern"}}
+	french := GlossaryEntries{{Source: "Save", Target: "Enregistrer"}}
+	body := &CreateMultilingualGlossaryParams{
+		Name:         "ui",
+		Dictionaries: []DictionaryParams{NewDictionary("en", "de", german), NewDictionary("en", "fr", french)},
+	}
+	glossary, err := deepl.CreateMultilingualGlossaryWithContext(ctx, body).Sync()
+	if err != nil || glossary.GlossaryId != testGlossaryId || len(glossary.Dictionaries) != 2 {
+		t.Fatalf("glossary %+v, err = %v", glossary, err)
+	}
+
+	patch := &UpdateMultilingualGlossaryParams{Dictionaries: []DictionaryParams{NewDictionary("en", "de", GlossaryEntries{{Source: "Open", Target: "Öffnen"}})}}
+	if _, err = deepl.UpdateMultilingualGlossaryWithContext(ctx, testGlossaryId, patch).Sync(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := deepl.DictionaryEntriesWithContext(ctx, testGlossaryId, "en", "de").Sync()
+	want := GlossaryEntries{{Source: "Save", Target: "Speichern"}, {Source: "Open", Target: "Öffnen"}}
+	if err != nil || !reflect.DeepEqual(entries, want) {
+		t.Fatalf("entries %v, err = %v", entries, err)
+	}
+
+	replacement := NewDictionary("en", "fr", GlossaryEntries{{Source: "Open", Target: "Ouvrir"}})
+	dictionary, err := deepl.ReplaceDictionaryWithContext(ctx, testGlossaryId, &replacement).Sync()
+	if err != nil || dictionary.EntryCount != 1 || server.dictionaries[[2]string{"en", "fr"}] != "Open\tOuvrir\n" {
+		t.Fatalf("dictionary %+v, err = %v", dictionary, err)
+	}
+
+	if glossary, err = deepl.RenameMultilingualGlossary(testGlossaryId, "ui-v2").Sync(); err != nil || glossary.Name != "ui-v2" {
+		t.Fatalf("glossary %+v, err = %v", glossary, err)
+	}
+	glossaries, err := deepl.ListMultilingualGlossariesWithContext(ctx).Sync()
+	if err != nil || len(glossaries) != 1 || glossaries[0].Name != "ui-v2" {
+		t.Fatalf("glossaries %v, err = %v", glossaries, err)
+	}
+
+	if _, err = deepl.DeleteDictionaryWithContext(ctx, testGlossaryId, "en", "fr").Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.dictionaries[[2]string{"en", "fr"}]; ok || len(server.dictionaries) != 1 {
+		t.Fatalf("dictionaries after delete %v", server.dictionaries)
+	}
+	if _, err = deepl.DeleteMultilingualGlossaryWithContext(ctx, testGlossaryId).Sync(); err != nil || server.dictionaries != nil {
+		t.Fatalf("err = %v, dictionaries %v", err, server.dictionaries)
+	}
+	if _, err = deepl.MultilingualGlossaryDetail("glossary_id").Sync(); err == nil {
+		t.Fatal("an invalid glossary id was sent")
+	}
+}
@@ -0,0 +1,135 @@
+package deepl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy Controls how failed requests are retried. Requests are retried when the
+// server answers 429, 529 or 5xx, or when a transient network error occurs.
+// Set MaxAttempts to 1 to disable retries.
+type RetryPolicy struct {
+	MaxAttempts int           // total number of attempts including the first one
+	BaseDelay   time.Duration // delay before the first retry, doubled on every further retry
+	MaxDelay    time.Duration // upper bound of a single delay including Retry-After, zero means unbounded
+	Jitter      float64       // random fraction in [0, 1] that the delay is reduced by
+}
+
+// delay Returns how long to wait before the next attempt, the Retry-After
+// header sent by the server takes precedence over the computed backoff
+func (self RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if self.MaxDelay > 0 && retryAfter > self.MaxDelay {
+			return self.MaxDelay
+		}
+		return retryAfter
+	}
+	backoff := float64(self.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if self.MaxDelay > 0 && backoff > float64(self.MaxDelay) {
+		backoff = float64(self.MaxDelay)
+	}
+	if self.Jitter > 0 {
+		backoff -= backoff * math.Min(self.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(backoff)
+}
+
+// retryableStatus Reports whether the response status code is worth retrying
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == 529 || code >= 500 && code <= 599
+}
+
+// transientError Reports whether the error returned by http.Client.Do is worth retrying.
+// Timeouts are retried, including the timeout of http.Client, unless the error is the
+// canceled or expired context of the caller.
+func transientError(err error) bool {
+	if callerContextError(err) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+// callerContextError Reports whether the error is caused by a canceled or expired context.
+// The errors are compared by identity, because the timeout error of http.Client also matches
+// context.DeadlineExceeded with errors.Is although no context of the caller expired.
+func callerContextError(err error) bool {
+	for err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return true
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, inner := range joined.Unwrap() {
+				if callerContextError(inner) {
+					return true
+				}
+			}
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// parseRetryAfter Parses the Retry-After header, which is either delay seconds or a http date
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// rewindRequest Returns a copy of the request whose body can be sent again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body can not be rewound")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone.Body = body
+	return clone, nil
+}
+
+// sleepWithContext Waits for the delay, returning early with the context error when the context is done
+func sleepWithContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,192 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    10 * time.Millisecond,
+}
+
+func TestRetryPolicy_delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{1, 0, 100 * time.Millisecond},
+		{2, 0, 200 * time.Millisecond},
+		{4, 0, 800 * time.Millisecond},
+		{5, 0, time.Second},
+		{1, 300 * time.Millisecond, 300 * time.Millisecond},
+		{1, time.Minute, time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.delay(tt.attempt, tt.retryAfter); got != tt.want {
+			t.Errorf("delay(%d, %s) = %s, want %s", tt.attempt, tt.retryAfter, got, tt.want)
+		}
+	}
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.delay(1, 0); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("jittered delay %s out of range", got)
+		}
+	}
+}
+
+func TestDeepl_Retry(t *testing.T) {
+	var calls int32
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !bytes.Contains(body, []byte("hello")) {
+			t.Errorf("attempt %d sent body %q", atomic.LoadInt32(&calls)+1, body)
+		}
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"translations":[{"text":"hallo"}]}`))
+		}
+	}, func(config *Config) {
+		config.Retry = testRetryPolicy
+	})
+	result, err := deepl.TextTranslate("hello", "DE").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Text != "hallo" || calls != 3 {
+		t.Fatalf("unexpected result %+v after %d calls", result, calls)
+	}
+}
+
+func TestDeepl_RetryExhausted(t *testing.T) {
+	var calls int32
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(529)
+	}, func(config *Config) {
+		config.Retry = testRetryPolicy
+	})
+	if _, err := deepl.Usage().Sync(); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
+
+func TestDeepl_RetryPermanentError(t *testing.T) {
+	var calls int32
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}, func(config *Config) {
+		config.Retry = testRetryPolicy
+	})
+	if _, err := deepl.Usage().Sync(); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDeepl_RetryContextCanceled(t *testing.T) {
+	var calls int32
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, func(config *Config) {
+		config.Retry = RetryPolicy{MaxAttempts: 3, MaxDelay: time.Minute}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := deepl.UsageWithContext(ctx).Sync(); err == nil {
+		t.Fatal("expected error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second || calls != 1 {
+		t.Fatalf("retry did not stop on context cancel, elapsed %s, calls %d", elapsed, calls)
+	}
+}
+
+// slowHandler Answers after the client gave up for the first slow requests, then answers the usage
+func slowHandler(calls *int32, slow int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= slow {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		json.NewEncoder(w).Encode(UsageResult{CharacterCount: 1})
+	}
+}
+
+func TestDeepl_RetryClientTimeout(t *testing.T) {
+	var calls int32
+	deepl := newTestDeepl(t, slowHandler(&calls, 2), func(config *Config) {
+		config.Timeout = 20 * time.Millisecond
+		config.Retry = testRetryPolicy
+	})
+	usage, err := deepl.Usage().Sync()
+	if calls := atomic.LoadInt32(&calls); err != nil || usage.CharacterCount != 1 || calls != 3 {
+		t.Fatalf("usage %+v, err = %v after %d calls, want the client timeouts to be retried", usage, err, calls)
+	}
+}
+
+func TestDeepl_RetryContextDeadline(t *testing.T) {
+	var calls int32
+	deepl := newTestDeepl(t, slowHandler(&calls, 3), func(config *Config) {
+		config.Retry = testRetryPolicy
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := deepl.UsageWithContext(ctx).Sync()
+	if calls := atomic.LoadInt32(&calls); !errors.Is(err, context.DeadlineExceeded) || calls != 1 {
+		t.Fatalf("err = %v after %d calls, want the expired context not to be retried", err, calls)
+	}
+}
+
+func TestDeepl_RetryDocumentBody(t *testing.T) {
+	var calls int32
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("attempt %d: %v", atomic.LoadInt32(&calls)+1, err)
+		} else if content, _ := io.ReadAll(file); string(content) != "document content" {
+			t.Errorf("attempt %d: file content %q", atomic.LoadInt32(&calls)+1, content)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"document_id":"id","document_key":"key"}`))
+	}, func(config *Config) {
+		config.Retry = testRetryPolicy
+	})
+	result, err := deepl.DocumentTranslate(strings.NewReader("document content"), "input.txt", "DE").Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DocumentId != "id" || calls != 2 {
+		t.Fatalf("unexpected result %+v after %d calls", result, calls)
+	}
+}
@@ -0,0 +1,195 @@
+// Package tmx reads and writes TMX 1.4 translation memory exchange documents.
+// Inline markup of segments is not preserved, only the plain text of a segment is kept.
+package tmx
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// DateFormat Is the format of the creationdate and changedate attributes
+const DateFormat = "20060102T150405Z"
+
+// xmlNamespace Is the namespace of the xml:lang attribute
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// AllLanguages Is the srclang of documents whose units have different source languages
+const AllLanguages = "*all*"
+
+type Document struct {
+	XMLName xml.Name `xml:"tmx"`
+	Version string   `xml:"version,attr"`
+	Header  Header   `xml:"header"`
+	Units   []Unit   `xml:"body>tu"`
+}
+
+type Header struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+	CreationDate        string `xml:"creationdate,attr,omitempty"`
+}
+
+// Unit Is a translation unit, a segment and its translations in other languages
+type Unit struct {
+	ID           string    `xml:"tuid,attr,omitempty"`
+	SrcLang      string    `xml:"srclang,attr,omitempty"`
+	CreationDate string    `xml:"creationdate,attr,omitempty"`
+	ChangeDate   string    `xml:"changedate,attr,omitempty"`
+	Variants     []Variant `xml:"tuv"`
+}
+
+// Variant Is the segment of a unit in a single language
+type Variant struct {
+	Lang    string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Segment string `xml:"seg"`
+}
+
+// UnmarshalXML Decodes a tuv element, the segment keeps the text of inline elements like <ph> or <hi>
+// without their markup
+func (self *Variant) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == xmlNamespace && attr.Name.Local == "lang" {
+			self.Lang = attr.Value
+		}
+	}
+	depth := 0
+	builder := strings.Builder{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			if depth > 0 || token.Name.Local == "seg" {
+				depth++
+			} else if err = decoder.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				self.Segment = builder.String()
+				return nil
+			}
+			depth--
+		case xml.CharData:
+			if depth > 0 {
+				builder.Write(token)
+			}
+		}
+	}
+}
+
+// New Creates an empty document, srcLang is the source language of all units or AllLanguages
+func New(srcLang string) *Document {
+	return &Document{
+		Version: "1.4",
+		Header: Header{
+			CreationTool:        "deepl-go",
+			CreationToolVersion: "1.0",
+			SegType:             "sentence",
+			OTMF:                "deepl-go",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+			CreationDate:        time.Now().UTC().Format(DateFormat),
+		},
+	}
+}
+
+// Parse Reads a TMX document
+func Parse(r io.Reader) (*Document, error) {
+	document := &Document{}
+	if err := xml.NewDecoder(r).Decode(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// Write Writes the document with an xml declaration
+func (self *Document) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(self); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Add Appends a unit with a segment in the source language and its translation
+func (self *Document) Add(sourceLang, source, targetLang, target string, created time.Time) {
+	unit := Unit{
+		Variants: []Variant{
+			{Lang: sourceLang, Segment: source},
+			{Lang: targetLang, Segment: target},
+		},
+	}
+	if !created.IsZero() {
+		unit.CreationDate = created.UTC().Format(DateFormat)
+	}
+	if self.Header.SrcLang != sourceLang {
+		unit.SrcLang = sourceLang
+	}
+	self.Units = append(self.Units, unit)
+}
+
+// SourceLang Returns the source language of the unit, which falls back to the srclang of the header
+func (self *Document) SourceLang(unit Unit) string {
+	if unit.SrcLang != "" && unit.SrcLang != AllLanguages {
+		return unit.SrcLang
+	}
+	if self.Header.SrcLang != AllLanguages {
+		return self.Header.SrcLang
+	}
+	if len(unit.Variants) > 0 {
+		return unit.Variants[0].Lang
+	}
+	return ""
+}
+
+// Segment Returns the segment of the unit in the language. Languages are compared case-insensitively,
+// when no variant matches exactly the first variant with the same primary language is returned,
+// which suits the srclang of a unit but may return another regional variant, e.g. pt-BR for pt-PT.
+func (self Unit) Segment(lang string) (string, bool) {
+	for _, variant := range self.Variants {
+		if strings.EqualFold(variant.Lang, lang) {
+			return variant.Segment, true
+		}
+	}
+	primary := PrimaryLanguage(lang)
+	for _, variant := range self.Variants {
+		if strings.EqualFold(PrimaryLanguage(variant.Lang), primary) {
+			return variant.Segment, true
+		}
+	}
+	return "", false
+}
+
+// Created Returns the change date of the unit, or its creation date when it was never changed
+func (self Unit) Created() time.Time {
+	for _, value := range []string{self.ChangeDate, self.CreationDate} {
+		if date, err := time.Parse(DateFormat, value); err == nil {
+			return date
+		}
+	}
+	return time.Time{}
+}
+
+// PrimaryLanguage Returns the primary subtag of a language tag, e.g. "en" for "en-US"
+func PrimaryLanguage(lang string) string {
+	if index := strings.IndexAny(lang, "-_"); index >= 0 {
+		return lang[:index]
+	}
+	return lang
+}
@@ -0,0 +1,91 @@
+package tmx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="vendor" creationtoolversion="2" segtype="sentence" o-tmf="vendor" adminlang="en-US" srclang="en-US" datatype="plaintext"/>
+  <body>
+    <tu tuid="1" creationdate="20240102T030405Z">
+      <tuv xml:lang="en-US"><seg>Save &amp; close</seg></tuv>
+      <tuv xml:lang="de-DE"><seg>Speichern &amp; schließen</seg></tuv>
+      <tuv xml:lang="pt-BR"><seg>Salvar e fechar</seg></tuv>
+    </tu>
+    <tu srclang="fr" changedate="20240203T000000Z" creationdate="20240102T000000Z">
+      <tuv xml:lang="fr"><seg>Bonjour</seg></tuv>
+      <tuv xml:lang="de-DE"><seg>Hallo</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+func TestParse(t *testing.T) {
+	document, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document.Version != "1.4" || document.Header.SrcLang != "en-US" || len(document.Units) != 2 {
+		t.Fatalf("unexpected document %+v", document)
+	}
+	unit := document.Units[0]
+	if unit.ID != "1" || len(unit.Variants) != 3 || unit.Variants[0].Lang != "en-US" {
+		t.Fatalf("unexpected unit %+v", unit)
+	}
+	if segment, ok := unit.Segment("DE"); !ok || segment != "Speichern & schließen" {
+		t.Fatalf("Segment(DE) = %q, %v", segment, ok)
+	}
+	if segment, ok := unit.Segment("pt-br"); !ok || segment != "Salvar e fechar" {
+		t.Fatalf("Segment(pt-br) = %q, %v", segment, ok)
+	}
+	if _, ok := unit.Segment("ja"); ok {
+		t.Fatal("Segment(ja) found a segment")
+	}
+	if document.SourceLang(unit) != "en-US" || document.SourceLang(document.Units[1]) != "fr" {
+		t.Fatal("unexpected source languages")
+	}
+	if created := document.Units[1].Created(); !created.Equal(time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Created = %s", created)
+	}
+}
+
+func TestDocument_Write(t *testing.T) {
+	document := New(AllLanguages)
+	document.Add("EN", "a < b", "DE", "a < b", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	buffer := &bytes.Buffer{}
+	if err := document.Write(buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<tmx version="1.4">`, `srclang="*all*"`, `<tu srclang="EN" creationdate="20240101T000000Z">`, `<tuv xml:lang="DE">`, `<seg>a &lt; b</seg>`} {
+		if !strings.Contains(buffer.String(), want) {
+			t.Fatalf("output does not contain %s:\n%s", want, buffer)
+		}
+	}
+	parsed, err := Parse(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segment, _ := parsed.Units[0].Segment("DE"); segment != "a < b" {
+		t.Fatalf("round trip segment = %q", segment)
+	}
+}
+
+func TestParse_InlineElements(t *testing.T) {
+	document, err := Parse(strings.NewReader(`<tmx version="1.4"><header srclang="en"/><body><tu>
+<tuv xml:lang="en"><prop type="x-note">ignored</prop><seg>Hello <ph>x</ph> world &amp; <hi type="b">more</hi></seg></tuv>
+<tuv xml:lang="de"><seg><hi>Hallo</hi> Welt</seg></tuv>
+</tu></body></tmx>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit := document.Units[0]
+	if segment, _ := unit.Segment("en"); segment != "Hello x world & more" {
+		t.Fatalf("Segment(en) = %q", segment)
+	}
+	if segment, _ := unit.Segment("de"); segment != "Hallo Welt" {
+		t.Fatalf("Segment(de) = %q", segment)
+	}
+}
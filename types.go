@@ -26,6 +26,7 @@ type TextTranslateParams struct {
 	NonSplittingTags     []string `json:"non_splitting_tags,omitempty"`
 	SplittingTags        []string `json:"splitting_tags,omitempty"`
 	IgnoreTags           []string `json:"ignore_tags,omitempty"`
+	ModelType            string   `json:"model_type,omitempty"`
 }
 
 type TextImprovementParams struct {
@@ -53,6 +54,7 @@ func (self *TextTranslateParams) recycle() {
 	self.Text = nil
 	self.SourceLang = ""
 	self.TargetLang = ""
+	self.Formality = ""
 	self.Context = ""
 	self.ShowBilledCharacters = false
 	self.SplitSentences = ""
@@ -63,6 +65,7 @@ func (self *TextTranslateParams) recycle() {
 	self.NonSplittingTags = nil
 	self.SplittingTags = nil
 	self.IgnoreTags = nil
+	self.ModelType = ""
 }
 
 func (self *DocumentTranslateParams) recycle() {
@@ -121,6 +124,8 @@ type CheckDocumentResult struct {
 	DocumentId       string `json:"document_id"`
 	Status           string `json:"status"`
 	SecondsRemaining int    `json:"seconds_remaining"`
+	BilledCharacters int    `json:"billed_characters,omitempty"`
+	ErrorMessage     string `json:"error_message,omitempty"`
 }
 
 type UsageResult struct {
@@ -147,3 +152,44 @@ type GlossaryResult struct {
 	CreationTime string `json:"creation_time"`
 	EntryCount   int64  `json:"entry_count"`
 }
+
+// DictionaryParams Is the entries of a single language pair of a multilingual glossary
+type DictionaryParams struct {
+	SourceLang    string `json:"source_lang"`
+	TargetLang    string `json:"target_lang"`
+	Entries       string `json:"entries"`
+	EntriesFormat string `json:"entries_format"`
+}
+
+type CreateMultilingualGlossaryParams struct {
+	Name         string             `json:"name"`
+	Dictionaries []DictionaryParams `json:"dictionaries"`
+}
+
+// UpdateMultilingualGlossaryParams Renames the glossary when Name is set and merges the entries
+// of Dictionaries into the dictionaries of the same language pairs
+type UpdateMultilingualGlossaryParams struct {
+	Name         string             `json:"name,omitempty"`
+	Dictionaries []DictionaryParams `json:"dictionaries,omitempty"`
+}
+
+type DictionaryResult struct {
+	SourceLang string `json:"source_lang"`
+	TargetLang string `json:"target_lang"`
+	EntryCount int64  `json:"entry_count"`
+}
+
+type MultilingualGlossaryResult struct {
+	GlossaryId   string             `json:"glossary_id"`
+	Name         string             `json:"name"`
+	Dictionaries []DictionaryResult `json:"dictionaries"`
+	CreationTime string             `json:"creation_time"`
+}
+
+type MultilingualGlossariesOptional struct {
+	Glossaries []*MultilingualGlossaryResult `json:"glossaries"`
+}
+
+type DictionaryEntriesOptional struct {
+	Dictionaries []DictionaryParams `json:"dictionaries"`
+}
@@ -0,0 +1,137 @@
+package deepl
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// ValidationError Is returned when a request is rejected before it is sent to deepl
+type ValidationError struct {
+	Field   string // the name of the invalid parameter, e.g. filename, size, output_format or formality
+	Value   string
+	Message string
+}
+
+func (self *ValidationError) Error() string {
+	return "invalid " + self.Field + " " + strconv.Quote(self.Value) + ": " + self.Message
+}
+
+// formalityLanguages Are the target languages that support the formality parameter,
+// used when the languages can not be fetched from deepl
+var formalityLanguages = map[Language]bool{
+	LanguageGerman:               true,
+	LanguageFrench:               true,
+	LanguageItalian:              true,
+	LanguageSpanish:              true,
+	LanguageSpanishLatinAmerican: true,
+	LanguageDutch:                true,
+	LanguagePolish:               true,
+	LanguagePortugueseBrazilian:  true,
+	LanguagePortugueseEuropean:   true,
+	LanguageJapanese:             true,
+	LanguageRussian:              true,
+}
+
+// resolveFormality Returns the formality to send for the target language. A hard formality is
+// downgraded to its prefer_* equivalent when the target language does not support formality,
+// or rejected when config.StrictFormality is set. Whether the target language supports formality
+// is looked up in the language catalog, falling back to formalityLanguages if it can not be fetched.
+func (self *Deepl) resolveFormality(ctx context.Context, formality, targetLang string) (string, error) {
+	if err := validateFormality(formality); err != nil {
+		return "", err
+	}
+	if formality != FormalityMore && formality != FormalityLess || targetLang == "" {
+		return formality, nil
+	}
+	supported, err := self.catalog.SupportsFormality(ctx, targetLang)
+	if err != nil {
+		supported = formalityLanguages[NormalizeLanguage(targetLang)]
+	}
+	if supported {
+		return formality, nil
+	}
+	if self.config.StrictFormality {
+		return "", &ValidationError{Field: "formality", Value: formality, Message: "target language " + targetLang + " does not support formality"}
+	}
+	if formality == FormalityMore {
+		return FormalityPreferMore, nil
+	}
+	return FormalityPreferLess, nil
+}
+
+// validateFormality Checks that the formality is one of the values deepl accepts
+func validateFormality(formality string) error {
+	switch formality {
+	case "", FormalityDefault, FormalityMore, FormalityLess, FormalityPreferMore, FormalityPreferLess:
+		return nil
+	}
+	return &ValidationError{Field: "formality", Value: formality, Message: "unknown formality"}
+}
+
+// validateDocument Checks the type, size and output format of the document before it is uploaded,
+// size is the size of the document in bytes or -1 when it is unknown
+func validateDocument(filename string, size int64, body *DocumentTranslateParams) error {
+	format, err := documentFormat(filename)
+	if err != nil {
+		return err
+	}
+	document := documentFormats[format]
+	if size > document.maxSize {
+		return &ValidationError{
+			Field:   "size",
+			Value:   strconv.FormatInt(size, 10),
+			Message: format + " documents can not be larger than " + strconv.FormatInt(document.maxSize, 10) + " bytes",
+		}
+	}
+	if output := strings.ToLower(body.OutputFormat); output != "" && !document.converts(output) {
+		return &ValidationError{
+			Field:   "output_format",
+			Value:   body.OutputFormat,
+			Message: format + " documents can only be translated to " + strings.Join(document.outputs, ", "),
+		}
+	}
+	return nil
+}
+
+// documentSize Returns the number of bytes left in the document, or -1 when it can not be determined
+func documentSize(document io.Reader) int64 {
+	if reader, ok := document.(nopReadCloser); ok {
+		document = reader.Reader
+	}
+	switch d := document.(type) {
+	case interface{ Len() int }:
+		return int64(d.Len())
+	case io.Seeker:
+		current, err := d.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := d.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err = d.Seek(current, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - current
+	case interface{ Stat() (fs.FileInfo, error) }:
+		info, err := d.Stat()
+		if err != nil {
+			return -1
+		}
+		return info.Size()
+	}
+	return -1
+}
+
+// nopReadCloser Is like io.NopCloser, but keeps the reader reachable so its size can be detected
+type nopReadCloser struct {
+	io.Reader
+}
+
+func (nopReadCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,217 @@
+package deepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestValidateDocument(t *testing.T) {
+	tests := []struct {
+		filename  string
+		size      int64
+		output    string
+		target    string
+		formality string
+		field     string
+	}{
+		{"report.docx", 1 << 20, "", "DE", FormalityMore, ""},
+		{"report.docx", -1, "pdf", "DE", "", ""},
+		{"scan.PDF", 30 << 20, "DOCX", "EN-US", FormalityPreferLess, ""},
+		{"legacy.doc", 10, "docx", "FR", "", ""},
+		{"archive.zip", 10, "", "DE", "", "filename"},
+		{"notes", 10, "", "DE", "", "filename"},
+		{"subtitles.srt", 150<<10 + 1, "", "DE", "", "size"},
+		{"page.html", 6 << 20, "", "DE", "", "size"},
+		{"notes.txt", 10, "pdf", "DE", "", "output_format"},
+		{"legacy.doc", 10, "doc", "DE", "", "output_format"},
+	}
+	for _, tt := range tests {
+		body := &DocumentTranslateParams{OutputFormat: tt.output}
+		body.TargetLang = tt.target
+		body.Formality = tt.formality
+		err := validateDocument(tt.filename, tt.size, body)
+		validationErr := &ValidationError{}
+		if tt.field == "" && err != nil || tt.field != "" && (!errors.As(err, &validationErr) || validationErr.Field != tt.field) {
+			t.Errorf("%s (%d bytes, output %q, %s %q): err = %v, want field %q", tt.filename, tt.size, tt.output, tt.target, tt.formality, err, tt.field)
+		}
+	}
+}
+
+func TestDocumentSize(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "input.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	file.WriteString("file content")
+	file.Seek(5, io.SeekStart)
+	fsys := fstest.MapFS{"input.txt": {Data: []byte("fs content")}}
+	fsFile, _ := fsys.Open("input.txt")
+	defer fsFile.Close()
+	reader := strings.NewReader("skipped:content")
+	reader.Seek(8, io.SeekStart)
+
+	tests := []struct {
+		document io.Reader
+		size     int64
+	}{
+		{reader, 7},
+		{bytes.NewBufferString("buffer"), 6},
+		{file, 7},
+		{fsFile, 10},
+		{nopReadCloser{strings.NewReader("wrapped")}, 7},
+		{onceReader{strings.NewReader("unknown")}, -1},
+	}
+	for i, tt := range tests {
+		if size := documentSize(tt.document); size != tt.size {
+			t.Errorf("%d: size = %d, want %d", i, size, tt.size)
+		}
+	}
+	if position, _ := file.Seek(0, io.SeekCurrent); position != 5 {
+		t.Errorf("documentSize moved the file to %d", position)
+	}
+}
+
+func TestDeepl_DocumentTranslateValidation(t *testing.T) {
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("an invalid document was uploaded to %s", r.URL.Path)
+	})
+	body := &DocumentTranslateParams{OutputFormat: "pdf"}
+	body.TargetLang = "DE"
+	_, err := deepl.DocumentTransWithParams(context.Background(), strings.NewReader("text"), "notes.txt", body).Sync()
+	validationErr := &ValidationError{}
+	if !errors.As(err, &validationErr) || validationErr.Field != "output_format" {
+		t.Fatalf("err = %v, want an output_format ValidationError", err)
+	}
+	var closed bool
+	open := func() (io.ReadCloser, error) {
+		return closeFunc{strings.NewReader(strings.Repeat("x", 1<<20+1)), func() { closed = true }}, nil
+	}
+	body.OutputFormat = ""
+	_, err = deepl.DocumentTranslateWithOpener(context.Background(), open, "large.txt", body).Sync()
+	if !errors.As(err, &validationErr) || validationErr.Field != "size" || !closed {
+		t.Fatalf("err = %v, closed %v, want a size ValidationError", err, closed)
+	}
+}
+
+type closeFunc struct {
+	*strings.Reader
+	close func()
+}
+
+func (self closeFunc) Close() error {
+	self.close()
+	return nil
+}
+
+func TestDeepl_FormalityFallback(t *testing.T) {
+	var requests int32
+	var sent TextTranslateParams
+	catalog := catalogHandler(t, &requests)
+	languagesAvailable := true
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != textTranslateUri {
+			if !languagesAvailable {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			catalog(w, r)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(TextTranslateResultOptional{Translations: []*TextResult{{Text: "translated"}}})
+	})
+
+	tests := []struct {
+		target, formality, want string
+	}{
+		{"DE", FormalityLess, FormalityLess},
+		{"en-gb", FormalityMore, FormalityPreferMore},
+		{"EN-US", FormalityLess, FormalityPreferLess},
+		{"EN-US", FormalityPreferMore, FormalityPreferMore},
+	}
+	translate := func(target, formality string) error {
+		sent = TextTranslateParams{}
+		body := &TextTranslateParams{Text: []string{"hello"}}
+		body.TargetLang = target
+		body.Formality = formality
+		_, err := deepl.TextTranslateWithParams(context.Background(), body).Sync()
+		if err == nil && body.Formality != formality {
+			t.Errorf("the formality of the params was changed to %s", body.Formality)
+		}
+		return err
+	}
+	for _, tt := range tests {
+		if err := translate(tt.target, tt.formality); err != nil || sent.Formality != tt.want {
+			t.Errorf("%s %s: sent formality %q, err = %v, want %q", tt.target, tt.formality, sent.Formality, err, tt.want)
+		}
+	}
+
+	validationErr := &ValidationError{}
+	if err := translate("DE", "polite"); !errors.As(err, &validationErr) || validationErr.Field != "formality" {
+		t.Errorf("err = %v, want a formality ValidationError", err)
+	}
+	deepl.config.StrictFormality = true
+	if err := translate("EN-US", FormalityMore); !errors.As(err, &validationErr) || validationErr.Field != "formality" {
+		t.Errorf("err = %v, want a formality ValidationError in strict mode", err)
+	}
+	deepl.config.StrictFormality = false
+
+	languagesAvailable = false
+	deepl.Catalog().Invalidate()
+	if err := translate("JA", FormalityMore); err != nil || sent.Formality != FormalityMore {
+		t.Errorf("sent formality %q, err = %v, want the formality of the static table", sent.Formality, err)
+	}
+	if err := translate("EN-GB", FormalityMore); err != nil || sent.Formality != FormalityPreferMore {
+		t.Errorf("sent formality %q, err = %v, want the formality of the static table", sent.Formality, err)
+	}
+}
+
+func TestDeepl_FormalityCatalogFailure(t *testing.T) {
+	var languages, translations int32
+	deepl := newTestDeepl(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == languagesUri {
+			atomic.AddInt32(&languages, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		atomic.AddInt32(&translations, 1)
+		json.NewEncoder(w).Encode(TextTranslateResultOptional{Translations: []*TextResult{{Text: "translated"}}})
+	}, func(config *Config) {
+		config.Retry = testRetryPolicy
+		config.Cache = NewLRUCache(10, time.Minute)
+	})
+	for _, text := range []string{"one", "two", "three"} {
+		body := &TextTranslateParams{Text: []string{text}}
+		body.TargetLang = "EN-GB"
+		body.Formality = FormalityMore
+		if _, err := deepl.TextTranslateWithParams(context.Background(), body).Sync(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if languages != int32(testRetryPolicy.MaxAttempts) || translations != 3 {
+		t.Fatalf("sent %d languages requests for 3 translations, want the failure to be remembered", languages)
+	}
+
+	deepl.Catalog().Invalidate()
+	body := &TextTranslateParams{Text: []string{"one"}}
+	body.TargetLang = "EN-GB"
+	body.Formality = FormalityMore
+	if _, err := deepl.TextTranslateWithParams(context.Background(), body).Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if languages != int32(testRetryPolicy.MaxAttempts) || translations != 3 {
+		t.Fatalf("a cached translation fetched the languages or was sent, %d languages requests, %d translations", languages, translations)
+	}
+}